@@ -7,18 +7,94 @@ import (
 	"reflect"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
 )
 
+var durationType = reflect.TypeOf(time.Duration(0))
+var timeType = reflect.TypeOf(time.Time{})
+
 type Binder struct {
-	opts *Options
+	opts      *Options
+	typ       reflect.Type
+	values    map[string]string
+	errs      []error
+	configKey string
+	fields    []*Field
+	layers    []*Layer
+	layersMu  sync.RWMutex
+
+	// obj is a copy of the struct whose values were used as defaults, so that the same
+	// defaults can be used again, e.g. by the config init command.
+	obj any
 }
 
 // Bind binds the config tags from the structs and binds flags to the cobra command.
 func Bind(obj any, cmd *cobra.Command, options ...*Options) error {
+	b := newBinder(options...)
+
+	t := reflect.TypeOf(obj)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	b.typ = t
+	if b.opts.UseStructValues {
+		err := b.structValues(obj)
+		if err != nil {
+			return err
+		}
+	}
+
+	err := b.processFields("", t, cmd)
+	if err != nil {
+		return err
+	}
+
+	if len(b.errs) > 0 {
+		return NewAggregateError(b.errs)
+	}
+
+	if b.hasProfiles() {
+		err = b.bindProfile(cmd)
+		if err != nil {
+			return err
+		}
+	}
+
+	if b.opts.ConfigCommand {
+		err = b.addConfigCommands(cmd)
+		if err != nil {
+			return err
+		}
+	}
+
+	if b.opts.ExplainFlag {
+		cmd.PersistentFlags().Bool(explainFlag, false, "Print the final value of each setting and where it came from")
+	}
+
+	if b.configKey != "" || len(b.opts.ConfigLayers) > 0 || b.opts.ExplainFlag || b.hasSecretFiles() {
+		addPreRun(cmd, b.preRun)
+	}
+
+	register(cmd, b)
+	return nil
+}
+
+// BindAll binds like Bind, but keeps going when a field fails to bind, returning every
+// error found rather than just the first.
+func BindAll(obj any, cmd *cobra.Command, options ...*Options) error {
+	opts := *newBinder(options...).opts
+	opts.AllErrors = true
+
+	return Bind(obj, cmd, &opts)
+}
+
+func newBinder(options ...*Options) *Binder {
 	b := &Binder{
 		opts: &Options{
 			Separator:      ".",
@@ -33,18 +109,64 @@ func Bind(obj any, cmd *cobra.Command, options ...*Options) error {
 		}
 	}
 
-	t := reflect.TypeOf(obj)
-	if t.Kind() == reflect.Ptr {
-		t = t.Elem()
+	return b
+}
+
+// skip reports an exported field that is not being bound to Options.OnSkip.
+func (b *Binder) skip(n string, reason string) {
+	if b.opts.OnSkip != nil {
+		b.opts.OnSkip(n, reason)
 	}
+}
+
+// structValues records the non-zero fields of obj as defaults, in the same form as the
+// defaults written in tags.
+func (b *Binder) structValues(obj any) error {
+	v := reflect.ValueOf(obj)
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+
+	c := reflect.New(v.Type())
+	c.Elem().Set(v)
+	b.obj = c.Interface()
+
+	b.values = map[string]string{}
+	return b.walkFields("", c.Elem(), func(n string, t *Tag, field reflect.StructField, v reflect.Value) error {
+		if v.IsZero() {
+			return nil
+		}
+
+		def, err := b.format(&Field{Key: n, Type: field.Type, Tag: t}, v)
+		if err != nil {
+			return NewParseError(fmt.Sprint(v.Interface()), field.Type.Kind(), n, err)
+		}
 
-	return b.processFields("", t, cmd)
+		b.values[n] = def
+		return nil
+	})
+}
+
+// format converts a field value into a default as it would be written in a tag, with
+// slices and maps as JSON.
+func (b *Binder) format(f *Field, v reflect.Value) (string, error) {
+	switch p := b.plain(f, v).(type) {
+	case string:
+		return p, nil
+	case []any, map[string]any:
+		s, err := json.Marshal(p)
+		return string(s), err
+	default:
+		return fmt.Sprint(p), nil
+	}
 }
 
 func (b *Binder) processFields(prefix string, t reflect.Type, cmd *cobra.Command) error {
 	for i := 0; i < t.NumField(); i++ {
 		err := b.processField(prefix, t.Field(i), cmd)
-		if err != nil {
+		if err != nil && b.opts.AllErrors {
+			b.errs = append(b.errs, err)
+		} else if err != nil {
 			return err
 		}
 	}
@@ -53,10 +175,7 @@ func (b *Binder) processFields(prefix string, t reflect.Type, cmd *cobra.Command
 }
 
 func (b *Binder) processField(prefix string, field reflect.StructField, cmd *cobra.Command) error {
-	n := strings.ToLower(field.Name)
-	if prefix != "" {
-		n = fmt.Sprintf("%s%s%s", prefix, b.opts.Separator, strings.ToLower(field.Name))
-	}
+	n := b.key(prefix, field)
 
 	if string(field.Name[0]) != strings.ToUpper(string(field.Name[0])) {
 		return nil
@@ -66,6 +185,7 @@ func (b *Binder) processField(prefix string, field reflect.StructField, cmd *cob
 	tag, present := field.Tag.Lookup("config")
 
 	if !present {
+		b.skip(n, "no config tag")
 		return nil
 	}
 
@@ -74,9 +194,78 @@ func (b *Binder) processField(prefix string, field reflect.StructField, cmd *cob
 		return NewTagParseError(tag, k, n, err)
 	}
 
+	if def, ok := b.values[n]; ok {
+		t.Default = def
+	}
+
+	if b.isConfigFile(n, t) {
+		b.configKey = n
+	}
+
+	desc := t.Description
+	choices := t.Choices()
+	if len(choices) > 0 {
+		t.Description = fmt.Sprintf("%s (one of: %s)", t.Description, strings.Join(choices, ", "))
+	}
+
+	env := b.env(n, t)
+	if env != "" {
+		t.Description = fmt.Sprintf("%s (env: %s)", t.Description, env)
+	}
+
 	f := b.flags(cmd, t)
-	switch k {
-	case reflect.Int:
+	switch {
+	case b.converter(field.Type) != nil:
+		c := b.converter(field.Type)
+		def := reflect.Zero(field.Type).Interface()
+		if t.Default != "" {
+			def, err = c.ParseDefault(t.Default)
+			if err != nil {
+				return NewParseError(t.Default, k, n, err)
+			}
+		}
+
+		f.VarP(c.NewValue(def), n, t.Shorthand, t.Description)
+	case field.Type == durationType:
+		var d time.Duration
+		if t.Default != "" {
+			d, err = time.ParseDuration(t.Default)
+			if err != nil {
+				return NewParseError(t.Default, k, n, err)
+			}
+		}
+
+		if t.Shorthand != "" {
+			f.DurationP(n, t.Shorthand, d, t.Description)
+		} else {
+			f.Duration(n, d, t.Description)
+		}
+	case field.Type == timeType:
+		var d time.Time
+		layout := b.layout(t)
+		if t.Default != "" {
+			d, err = time.Parse(layout, t.Default)
+			if err != nil {
+				return NewParseError(t.Default, k, n, err)
+			}
+		}
+
+		if t.Shorthand != "" {
+			f.TimeP(n, t.Shorthand, d, []string{layout}, t.Description)
+		} else {
+			f.Time(n, d, []string{layout}, t.Description)
+		}
+	case isCustom(field.Type):
+		v, _ := newCustom(field.Type)
+		if t.Default != "" {
+			err = v.Set(t.Default)
+			if err != nil {
+				return NewParseError(t.Default, k, n, err)
+			}
+		}
+
+		f.VarP(v, n, t.Shorthand, t.Description)
+	case k == reflect.Int:
 		var i int
 		if t.Default != "" {
 			i, err = strconv.Atoi(t.Default)
@@ -90,13 +279,13 @@ func (b *Binder) processField(prefix string, field reflect.StructField, cmd *cob
 		} else {
 			f.Int(n, i, t.Description)
 		}
-	case reflect.String:
+	case k == reflect.String:
 		if t.Shorthand != "" {
 			f.StringP(n, t.Shorthand, t.Default, t.Description)
 		} else {
 			f.String(n, t.Default, t.Description)
 		}
-	case reflect.Float64:
+	case k == reflect.Float64:
 		var i float64
 		if t.Default != "" {
 			i, err = strconv.ParseFloat(t.Default, 64)
@@ -110,7 +299,7 @@ func (b *Binder) processField(prefix string, field reflect.StructField, cmd *cob
 		} else {
 			f.Float64(n, i, t.Description)
 		}
-	case reflect.Float32:
+	case k == reflect.Float32:
 		var i float64
 		if t.Default != "" {
 			i, err = strconv.ParseFloat(t.Default, 32)
@@ -124,7 +313,7 @@ func (b *Binder) processField(prefix string, field reflect.StructField, cmd *cob
 		} else {
 			f.Float32(n, float32(i), t.Description)
 		}
-	case reflect.Int8:
+	case k == reflect.Int8:
 		var i int64
 		if t.Default != "" {
 			i, err = strconv.ParseInt(t.Default, 0, 8)
@@ -138,7 +327,7 @@ func (b *Binder) processField(prefix string, field reflect.StructField, cmd *cob
 		} else {
 			f.Int8(n, int8(i), t.Description)
 		}
-	case reflect.Int16:
+	case k == reflect.Int16:
 		var i int64
 		if t.Default != "" {
 			i, err = strconv.ParseInt(t.Default, 0, 16)
@@ -152,7 +341,7 @@ func (b *Binder) processField(prefix string, field reflect.StructField, cmd *cob
 		} else {
 			f.Int16(n, int16(i), t.Description)
 		}
-	case reflect.Int32:
+	case k == reflect.Int32:
 		var i int64
 		if t.Default != "" {
 			i, err = strconv.ParseInt(t.Default, 0, 32)
@@ -166,7 +355,7 @@ func (b *Binder) processField(prefix string, field reflect.StructField, cmd *cob
 		} else {
 			f.Int32(n, int32(i), t.Description)
 		}
-	case reflect.Int64:
+	case k == reflect.Int64:
 		var i int64
 		if t.Default != "" {
 			i, err = strconv.ParseInt(t.Default, 0, 64)
@@ -180,165 +369,367 @@ func (b *Binder) processField(prefix string, field reflect.StructField, cmd *cob
 		} else {
 			f.Int64(n, int64(i), t.Description)
 		}
-	case reflect.Bool:
-		var i bool
+	case k == reflect.Uint:
+		var i uint64
 		if t.Default != "" {
-			i, err = strconv.ParseBool(t.Default)
+			i, err = strconv.ParseUint(t.Default, 0, strconv.IntSize)
 			if err != nil {
 				return NewParseError(t.Default, k, n, err)
 			}
 		}
 
 		if t.Shorthand != "" {
-			f.BoolP(n, t.Shorthand, i, t.Description)
+			f.UintP(n, t.Shorthand, uint(i), t.Description)
 		} else {
-			f.Bool(n, i, t.Description)
-		}
-	case reflect.Array, reflect.Slice:
-		err := b.processSlice(n, t, field, cmd)
-		if err != nil && errors.Is(err, InvalidTypeError) {
-			return nil
-		} else if err != nil {
-			return NewParseError(t.Default, k, n, err)
-		}
-	case reflect.Struct:
-		return b.processFields(n, field.Type, cmd)
-	case reflect.Ptr:
-		if field.Type.Elem().Kind() == reflect.Struct && b.opts.PrefixEmbedded {
-			n = prefix
+			f.Uint(n, uint(i), t.Description)
 		}
-		return b.processFields(n, field.Type.Elem(), cmd)
-	default:
-		return NewInvalidTypeError(field.Type.Kind(), n)
-	}
-
-	err = viper.BindPFlag(n, f.Lookup(n))
-	if err != nil {
-		return NewBindError(field.Type.Kind(), n, err)
-	}
-
-	return nil
-}
-
-type jsonStruct struct {
-	StringArray  []string  `json:"stringarray,omitempty"`
-	IntArray     []int     `json:"intarray,omitempty"`
-	Float64Array []float64 `json:"float64array,omitempty"`
-	Float32Array []float32 `json:"float32array,omitempty"`
-	Int32Array   []int32   `json:"int32array,omitempty"`
-	Int64Array   []int64   `json:"int64array,omitempty"`
-	BoolArray    []bool    `json:"boolarray,omitempty"`
-}
-
-func (b *Binder) processSlice(n string, t *Tag, field reflect.StructField, cmd *cobra.Command) (err error) {
-	s := &jsonStruct{}
-	k := field.Type.Kind()
-	f := b.flags(cmd, t)
-	switch field.Type.Elem().Kind() {
-	case reflect.Int:
+	case k == reflect.Uint8:
+		var i uint64
 		if t.Default != "" {
-			def := fmt.Sprintf("{\"intarray\":%s}", t.Default)
-			err := json.Unmarshal([]byte(def), &s)
+			i, err = strconv.ParseUint(t.Default, 0, 8)
 			if err != nil {
 				return NewParseError(t.Default, k, n, err)
 			}
 		}
 
 		if t.Shorthand != "" {
-			f.IntSliceP(n, t.Shorthand, s.IntArray, t.Description)
+			f.Uint8P(n, t.Shorthand, uint8(i), t.Description)
 		} else {
-			f.IntSlice(n, s.IntArray, t.Description)
+			f.Uint8(n, uint8(i), t.Description)
 		}
-	case reflect.String:
+	case k == reflect.Uint16:
+		var i uint64
 		if t.Default != "" {
-			def := fmt.Sprintf("{\"stringarray\":%s}", t.Default)
-			err := json.Unmarshal([]byte(def), &s)
+			i, err = strconv.ParseUint(t.Default, 0, 16)
 			if err != nil {
 				return NewParseError(t.Default, k, n, err)
 			}
 		}
 
 		if t.Shorthand != "" {
-			f.StringSliceP(n, t.Shorthand, s.StringArray, t.Description)
+			f.Uint16P(n, t.Shorthand, uint16(i), t.Description)
 		} else {
-			f.StringSlice(n, s.StringArray, t.Description)
+			f.Uint16(n, uint16(i), t.Description)
 		}
-	case reflect.Float64:
+	case k == reflect.Uint32:
+		var i uint64
 		if t.Default != "" {
-			def := fmt.Sprintf("{\"float64array\":%s}", t.Default)
-			err := json.Unmarshal([]byte(def), &s)
+			i, err = strconv.ParseUint(t.Default, 0, 32)
 			if err != nil {
 				return NewParseError(t.Default, k, n, err)
 			}
 		}
 
 		if t.Shorthand != "" {
-			f.Float64SliceP(n, t.Shorthand, s.Float64Array, t.Description)
+			f.Uint32P(n, t.Shorthand, uint32(i), t.Description)
 		} else {
-			f.Float64Slice(n, s.Float64Array, t.Description)
+			f.Uint32(n, uint32(i), t.Description)
 		}
-	case reflect.Float32:
+	case k == reflect.Uint64:
+		var i uint64
 		if t.Default != "" {
-			def := fmt.Sprintf("{\"float32array\":%s}", t.Default)
-			err := json.Unmarshal([]byte(def), &s)
+			i, err = strconv.ParseUint(t.Default, 0, 64)
 			if err != nil {
 				return NewParseError(t.Default, k, n, err)
 			}
 		}
+
 		if t.Shorthand != "" {
-			f.Float32SliceP(n, t.Shorthand, s.Float32Array, t.Description)
+			f.Uint64P(n, t.Shorthand, uint64(i), t.Description)
 		} else {
-			f.Float32Slice(n, s.Float32Array, t.Description)
+			f.Uint64(n, uint64(i), t.Description)
 		}
-	case reflect.Int32:
+	case k == reflect.Bool:
+		var i bool
 		if t.Default != "" {
-			def := fmt.Sprintf("{\"int32array\":%s}", t.Default)
-			err := json.Unmarshal([]byte(def), &s)
+			i, err = strconv.ParseBool(t.Default)
 			if err != nil {
 				return NewParseError(t.Default, k, n, err)
 			}
 		}
+
+		if t.Shorthand != "" {
+			f.BoolP(n, t.Shorthand, i, t.Description)
+		} else {
+			f.Bool(n, i, t.Description)
+		}
+	case b.isStructSlice(field.Type):
+		err := b.processStructSlice(n, t, field, cmd)
+		if err != nil {
+			return err
+		}
+	case k == reflect.Array, k == reflect.Slice:
+		err := b.processSlice(n, t, field, cmd)
+		if err != nil && errors.Is(err, InvalidTypeError) && !b.opts.Strict {
+			b.skip(n, err.Error())
+			return nil
+		} else if err != nil && errors.Is(err, InvalidTypeError) {
+			return err
+		} else if err != nil {
+			return NewParseError(t.Default, k, n, err)
+		}
+	case k == reflect.Map:
+		err := b.processMap(n, t, field, cmd)
+		if err != nil {
+			return err
+		}
+	case k == reflect.Struct:
+		return b.processFields(n, field.Type, cmd)
+	case k == reflect.Ptr && field.Type.Elem().Kind() != reflect.Struct:
+		return NewInvalidTypeError(k, n)
+	case k == reflect.Ptr:
+		return b.processFields(b.embeddedKey(prefix, n, field), field.Type.Elem(), cmd)
+	default:
+		return NewInvalidTypeError(field.Type.Kind(), n)
+	}
+
+	if len(choices) > 0 {
+		err = b.restrict(cmd, f.Lookup(n), choices)
+		if err != nil {
+			return NewBindError(field.Type.Kind(), n, err)
+		}
+	}
+
+	if t.Secret {
+		hideDefault(f.Lookup(n))
+	}
+
+	err = b.viper().BindPFlag(n, f.Lookup(n))
+	if err != nil {
+		return NewBindError(field.Type.Kind(), n, err)
+	}
+
+	if env != "" {
+		err = b.viper().BindEnv(n, env)
+		if err != nil {
+			return NewBindError(field.Type.Kind(), n, err)
+		}
+	}
+
+	b.fields = append(b.fields, &Field{Key: n, Env: env, Type: field.Type, Tag: t, Flag: f.Lookup(n), Description: desc})
+	return nil
+}
+
+func (b *Binder) processSlice(n string, t *Tag, field reflect.StructField, cmd *cobra.Command) (err error) {
+	k := field.Type.Kind()
+	f := b.flags(cmd, t)
+	e := field.Type.Elem()
+	switch {
+	case e == durationType:
+		err = b.processDurations(n, t, f)
+	case isBytes(field.Type):
+		err = b.processBytes(n, t, f)
+	case e.Kind() == reflect.String:
+		err = addSlice(f.StringSliceP, n, t)
+	case e.Kind() == reflect.Bool:
+		err = addSlice(f.BoolSliceP, n, t)
+	case e.Kind() == reflect.Int:
+		err = addSlice(f.IntSliceP, n, t)
+	case e.Kind() == reflect.Int8:
+		err = addSliceValue(f, n, t, "int8Slice", parseInt[int8](8))
+	case e.Kind() == reflect.Int16:
+		err = addSliceValue(f, n, t, "int16Slice", parseInt[int16](16))
+	case e.Kind() == reflect.Int32:
+		err = addSlice(f.Int32SliceP, n, t)
+	case e.Kind() == reflect.Int64:
+		err = addSlice(f.Int64SliceP, n, t)
+	case e.Kind() == reflect.Uint:
+		err = addSlice(f.UintSliceP, n, t)
+	case e.Kind() == reflect.Uint8:
+		err = addSliceValue(f, n, t, "uint8Slice", parseUint[uint8](8))
+	case e.Kind() == reflect.Uint16:
+		err = addSliceValue(f, n, t, "uint16Slice", parseUint[uint16](16))
+	case e.Kind() == reflect.Uint32:
+		err = addSliceValue(f, n, t, "uint32Slice", parseUint[uint32](32))
+	case e.Kind() == reflect.Uint64:
+		err = addSliceValue(f, n, t, "uint64Slice", parseUint[uint64](64))
+	case e.Kind() == reflect.Float32:
+		err = addSlice(f.Float32SliceP, n, t)
+	case e.Kind() == reflect.Float64:
+		err = addSlice(f.Float64SliceP, n, t)
+	default:
+		return NewInvalidTypeError(k, n, fmt.Errorf("unsupported element type %s", e))
+	}
+
+	if err != nil {
+		return err
+	}
+
+	err = b.viper().BindPFlag(n, f.Lookup(n))
+	if err != nil {
+		return NewBindError(k, n, err)
+	}
+
+	return err
+}
+
+// processDurations binds a slice of durations, with a default written as a list of
+// strings, e.g. `["1s","1m"]`.
+func (b *Binder) processDurations(n string, t *Tag, f *pflag.FlagSet) error {
+	s, err := parseSlice[string](t.Default)
+	if err != nil {
+		return err
+	}
+
+	d := make([]time.Duration, len(s))
+	for i, item := range s {
+		d[i], err = time.ParseDuration(item)
+		if err != nil {
+			return err
+		}
+	}
+
+	f.DurationSliceP(n, t.Shorthand, d, t.Description)
+	return nil
+}
+
+// processBytes binds a byte slice as a single flag holding the bytes as base64, or as hex
+// when the tag has `encoding=hex`.
+func (b *Binder) processBytes(n string, t *Tag, f *pflag.FlagSet) error {
+	d, err := decodeBytes(t.Default, t.Encoding)
+	if err != nil {
+		return err
+	}
+
+	if t.Encoding == encodingHex {
+		f.BytesHexP(n, t.Shorthand, d, t.Description)
+	} else {
+		f.BytesBase64P(n, t.Shorthand, d, t.Description)
+	}
+
+	return nil
+}
+
+func (b *Binder) processMap(n string, t *Tag, field reflect.StructField, cmd *cobra.Command) error {
+	k := field.Type.Kind()
+	f := b.flags(cmd, t)
+	if field.Type.Key().Kind() != reflect.String {
+		return NewInvalidTypeError(k, n)
+	}
+
+	switch field.Type.Elem().Kind() {
+	case reflect.String:
+		m := map[string]string{}
+		err := parseMap(t.Default, &m)
+		if err != nil {
+			return NewParseError(t.Default, k, n, err)
+		}
+
 		if t.Shorthand != "" {
-			f.Int32SliceP(n, t.Shorthand, s.Int32Array, t.Description)
+			f.StringToStringP(n, t.Shorthand, m, t.Description)
 		} else {
-			f.Int32Slice(n, s.Int32Array, t.Description)
+			f.StringToString(n, m, t.Description)
 		}
-	case reflect.Int64:
-		if t.Default != "" {
-			def := fmt.Sprintf("{\"int64array\":%s}", t.Default)
-			err := json.Unmarshal([]byte(def), &s)
-			if err != nil {
-				return NewParseError(t.Default, k, n, err)
-			}
+	case reflect.Int:
+		m := map[string]int{}
+		err := parseMap(t.Default, &m)
+		if err != nil {
+			return NewParseError(t.Default, k, n, err)
 		}
+
 		if t.Shorthand != "" {
-			f.Int64SliceP(n, t.Shorthand, s.Int64Array, t.Description)
+			f.StringToIntP(n, t.Shorthand, m, t.Description)
 		} else {
-			f.Int64Slice(n, s.Int64Array, t.Description)
+			f.StringToInt(n, m, t.Description)
 		}
-	case reflect.Bool:
-		if t.Default != "" {
-			def := fmt.Sprintf("{\"boolarray\":%s}", t.Default)
-			err := json.Unmarshal([]byte(def), &s)
-			if err != nil {
-				return NewParseError(t.Default, k, n, err)
-			}
+	case reflect.Int64:
+		m := map[string]int64{}
+		err := parseMap(t.Default, &m)
+		if err != nil {
+			return NewParseError(t.Default, k, n, err)
 		}
+
 		if t.Shorthand != "" {
-			f.BoolSliceP(n, t.Shorthand, s.BoolArray, t.Description)
+			f.StringToInt64P(n, t.Shorthand, m, t.Description)
 		} else {
-			f.BoolSlice(n, s.BoolArray, t.Description)
+			f.StringToInt64(n, m, t.Description)
 		}
 	default:
 		return NewInvalidTypeError(k, n)
 	}
 
-	err = viper.BindPFlag(n, f.Lookup(n))
+	return nil
+}
+
+// parseMap parses a map default given either as json, e.g. `{"a":1}`, or as a list
+// of pairs, e.g. `a=1,b=2`.
+func parseMap(def string, out any) error {
+	def = strings.TrimSpace(def)
+	if def == "" {
+		return nil
+	}
+
+	if strings.HasPrefix(def, "{") {
+		return json.Unmarshal([]byte(def), out)
+	}
+
+	m, err := toMap(def)
 	if err != nil {
-		return NewBindError(k, n, err)
+		return err
 	}
 
-	return err
+	return decode(m, reflect.ValueOf(out).Elem())
+}
+
+// isLeaf reports whether a struct or pointer typed field holds a single value, rather
+// than being a nested config struct whose fields should be walked.
+func (b *Binder) isLeaf(t reflect.Type) bool {
+	return b.converter(t) != nil || t == timeType || isCustom(t)
+}
+
+// layout returns the layout used to parse time.Time fields.
+func (b *Binder) layout(t *Tag) string {
+	if t.Layout != "" {
+		return t.Layout
+	}
+
+	if b.opts.TimeLayout != "" {
+		return b.opts.TimeLayout
+	}
+
+	return time.RFC3339
+}
+
+// key returns the flag and viper key for a field nested under the given prefix.
+func (b *Binder) key(prefix string, field reflect.StructField) string {
+	if prefix == "" {
+		return strings.ToLower(field.Name)
+	}
+
+	return fmt.Sprintf("%s%s%s", prefix, b.opts.Separator, strings.ToLower(field.Name))
+}
+
+// embeddedKey returns the prefix used for the fields of a struct pointer field.
+func (b *Binder) embeddedKey(prefix, n string, field reflect.StructField) string {
+	if field.Type.Elem().Kind() == reflect.Struct && b.opts.PrefixEmbedded {
+		return prefix
+	}
+
+	return n
+}
+
+// env returns the environment variable name for a key, or an empty string if the
+// field should not be bound to the environment.
+func (b *Binder) env(n string, t *Tag) string {
+	if t.Env != "" {
+		return t.Env
+	}
+
+	if b.opts.EnvPrefix == "" {
+		return ""
+	}
+
+	r := strings.NewReplacer(b.opts.Separator, "_", "-", "_", ".", "_")
+	return strings.ToUpper(fmt.Sprintf("%s_%s", strings.TrimSuffix(b.opts.EnvPrefix, "_"), r.Replace(n)))
+}
+
+// viper returns the viper instance to bind against, falling back to the global instance.
+func (b *Binder) viper() *viper.Viper {
+	if b.opts.Viper != nil {
+		return b.opts.Viper
+	}
+
+	return viper.GetViper()
 }
 
 func (b *Binder) flags(cmd *cobra.Command, t *Tag) *pflag.FlagSet {
@@ -4,8 +4,10 @@ import (
 	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
 // Int.
@@ -240,6 +242,252 @@ func TestBindBoolInvalidDefaultReturnsError(t *testing.T) {
 	assertError(t, err)
 }
 
+// Uint.
+type BindUintSetsDefault struct {
+	UintTest uint `config:"21,The uint to test"`
+}
+
+func TestBindUintSetsDefault(t *testing.T) {
+	cmd := &cobra.Command{}
+	err := Bind(BindUintSetsDefault{}, cmd)
+	assertNil(t, err)
+
+	i, err := cmd.Flags().GetUint("uinttest")
+
+	assertEqual(t, uint(21), i)
+	assertNil(t, err)
+}
+
+type BindUintInvalidDefaultReturnsError struct {
+	UintTest uint `config:"-1,The Uint to test"`
+}
+
+func TestBindUintInvalidDefaultReturnsError(t *testing.T) {
+	cmd := &cobra.Command{}
+	err := Bind(BindUintInvalidDefaultReturnsError{}, cmd)
+
+	assertError(t, err)
+}
+
+// Uint8.
+type BindUint8SetsDefault struct {
+	Uint8Test uint8 `config:"8,The uint8 to test"`
+}
+
+func TestBindUint8SetsDefault(t *testing.T) {
+	cmd := &cobra.Command{}
+	err := Bind(BindUint8SetsDefault{}, cmd)
+	assertNil(t, err)
+
+	i, err := cmd.Flags().GetUint8("uint8test")
+
+	assertEqual(t, uint8(8), i)
+	assertNil(t, err)
+}
+
+type BindUint8InvalidDefaultReturnsError struct {
+	Uint8Test uint8 `config:"256,The Uint8 to test"`
+}
+
+func TestBindUint8InvalidDefaultReturnsError(t *testing.T) {
+	cmd := &cobra.Command{}
+	err := Bind(BindUint8InvalidDefaultReturnsError{}, cmd)
+
+	assertError(t, err)
+}
+
+// Uint16.
+type BindUint16SetsDefault struct {
+	Uint16Test uint16 `config:"16,The uint16 to test"`
+}
+
+func TestBindUint16SetsDefault(t *testing.T) {
+	cmd := &cobra.Command{}
+	err := Bind(BindUint16SetsDefault{}, cmd)
+	assertNil(t, err)
+
+	i, err := cmd.Flags().GetUint16("uint16test")
+
+	assertEqual(t, uint16(16), i)
+	assertNil(t, err)
+}
+
+type BindUint16InvalidDefaultReturnsError struct {
+	Uint16Test uint16 `config:"65536,The Uint16 to test"`
+}
+
+func TestBindUint16InvalidDefaultReturnsError(t *testing.T) {
+	cmd := &cobra.Command{}
+	err := Bind(BindUint16InvalidDefaultReturnsError{}, cmd)
+
+	assertError(t, err)
+}
+
+// Uint32.
+type BindUint32SetsDefault struct {
+	Uint32Test uint32 `config:"32,The uint32 to test"`
+}
+
+func TestBindUint32SetsDefault(t *testing.T) {
+	cmd := &cobra.Command{}
+	err := Bind(BindUint32SetsDefault{}, cmd)
+	assertNil(t, err)
+
+	i, err := cmd.Flags().GetUint32("uint32test")
+
+	assertEqual(t, uint32(32), i)
+	assertNil(t, err)
+}
+
+type BindUint32InvalidDefaultReturnsError struct {
+	Uint32Test uint32 `config:"FOO,The Uint32 to test"`
+}
+
+func TestBindUint32InvalidDefaultReturnsError(t *testing.T) {
+	cmd := &cobra.Command{}
+	err := Bind(BindUint32InvalidDefaultReturnsError{}, cmd)
+
+	assertError(t, err)
+}
+
+// Uint64.
+type BindUint64SetsDefault struct {
+	Uint64Test uint64 `config:"64,The uint64 to test"`
+}
+
+func TestBindUint64SetsDefault(t *testing.T) {
+	cmd := &cobra.Command{}
+	err := Bind(BindUint64SetsDefault{}, cmd)
+	assertNil(t, err)
+
+	i, err := cmd.Flags().GetUint64("uint64test")
+
+	assertEqual(t, uint64(64), i)
+	assertNil(t, err)
+}
+
+type BindUint64InvalidDefaultReturnsError struct {
+	Uint64Test uint64 `config:"-64,The Uint64 to test"`
+}
+
+func TestBindUint64InvalidDefaultReturnsError(t *testing.T) {
+	cmd := &cobra.Command{}
+	err := Bind(BindUint64InvalidDefaultReturnsError{}, cmd)
+
+	assertError(t, err)
+}
+
+// UintSlice.
+type BindUintSliceSetsDefault struct {
+	UintSliceTest []uint `config:"\"[12,13,14,15]\",The UintSlice to test"`
+}
+
+func TestBindUintSliceSetsDefault(t *testing.T) {
+	cmd := &cobra.Command{}
+	err := Bind(BindUintSliceSetsDefault{}, cmd)
+	assertNil(t, err)
+
+	i, err := cmd.Flags().GetUintSlice("uintslicetest")
+
+	assertSliceEqual(t, []uint{12, 13, 14, 15}, i)
+	assertNil(t, err)
+}
+
+type BindUintSliceInvalidDefaultReturnsError struct {
+	UintSliceTest []uint `config:"\"[-1]\",The UintSlice to test"`
+}
+
+func TestBindUintSliceInvalidDefaultReturnsError(t *testing.T) {
+	cmd := &cobra.Command{}
+	err := Bind(BindUintSliceInvalidDefaultReturnsError{}, cmd)
+
+	assertError(t, err)
+}
+
+func TestBindUintOverflowNamesField(t *testing.T) {
+	cmd := &cobra.Command{}
+	err := Bind(BindUint8InvalidDefaultReturnsError{}, cmd)
+
+	var perr *parseError
+	if !errors.As(err, &perr) {
+		t.Fatalf("expected a parse error but got %v", err)
+	}
+
+	assertEqual(t, "uint8test", perr.FieldName)
+}
+
+// Duration.
+type BindDurationSetsDefault struct {
+	DurationTest time.Duration `config:"1m30s,The Duration to test"`
+}
+
+func TestBindDurationSetsDefault(t *testing.T) {
+	cmd := &cobra.Command{}
+	err := Bind(BindDurationSetsDefault{}, cmd)
+	assertNil(t, err)
+
+	i, err := cmd.Flags().GetDuration("durationtest")
+
+	assertEqual(t, 90*time.Second, i)
+	assertNil(t, err)
+}
+
+type BindDurationInvalidDefaultReturnsError struct {
+	DurationTest time.Duration `config:"30,The Duration to test"`
+}
+
+func TestBindDurationInvalidDefaultReturnsError(t *testing.T) {
+	cmd := &cobra.Command{}
+	err := Bind(BindDurationInvalidDefaultReturnsError{}, cmd)
+
+	assertErrorIs(t, err, &parseError{})
+}
+
+// Time.
+type BindTimeSetsDefault struct {
+	TimeTest time.Time `config:"2024-01-02T03:04:05Z,The Time to test"`
+}
+
+func TestBindTimeSetsDefault(t *testing.T) {
+	cmd := &cobra.Command{}
+	err := Bind(BindTimeSetsDefault{}, cmd)
+	assertNil(t, err)
+
+	i, err := cmd.Flags().GetTime("timetest")
+
+	assertEqual(t, time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), i)
+	assertNil(t, err)
+}
+
+type BindTimeUsesLayout struct {
+	DateTest time.Time `config:"default=2024-01-02;desc=The Time to test;layout=2006-01-02"`
+}
+
+func TestBindTimeUsesLayout(t *testing.T) {
+	cmd := &cobra.Command{}
+	err := Bind(BindTimeUsesLayout{}, cmd)
+	assertNil(t, err)
+
+	err = cmd.ParseFlags([]string{"--datetest", "2025-06-07"})
+	assertNil(t, err)
+
+	i, err := cmd.Flags().GetTime("datetest")
+
+	assertEqual(t, time.Date(2025, 6, 7, 0, 0, 0, 0, time.UTC), i)
+	assertNil(t, err)
+}
+
+type BindTimeInvalidDefaultReturnsError struct {
+	TimeTest time.Time `config:"yesterday,The Time to test"`
+}
+
+func TestBindTimeInvalidDefaultReturnsError(t *testing.T) {
+	cmd := &cobra.Command{}
+	err := Bind(BindTimeInvalidDefaultReturnsError{}, cmd)
+
+	assertErrorIs(t, err, &parseError{})
+}
+
 // IntSlice.
 type BindIntSliceSetsDefault struct {
 	IntSliceTest []int `config:"\"[12,13,14,15]\",The IntSlice to test"`
@@ -429,6 +677,199 @@ func TestBindBoolSliceInvalidDefaultReturnsError(t *testing.T) {
 	assertError(t, err)
 }
 
+// StringToString.
+type BindStringMapSetsDefault struct {
+	StringMapTest map[string]string `config:"\"{\"\"a\"\":\"\"x\"\",\"\"b\"\":\"\"y\"\"}\",The StringMap to test"`
+}
+
+func TestBindStringMapSetsDefault(t *testing.T) {
+	cmd := &cobra.Command{}
+	err := Bind(BindStringMapSetsDefault{}, cmd)
+	assertNil(t, err)
+
+	m, err := cmd.Flags().GetStringToString("stringmaptest")
+
+	assertEqual(t, "x", m["a"])
+	assertEqual(t, "y", m["b"])
+	assertNil(t, err)
+}
+
+// StringToInt.
+type BindIntMapSetsDefault struct {
+	IntMapTest map[string]int `config:"\"a=1,b=2\",The IntMap to test"`
+}
+
+func TestBindIntMapSetsDefault(t *testing.T) {
+	cmd := &cobra.Command{}
+	err := Bind(BindIntMapSetsDefault{}, cmd)
+	assertNil(t, err)
+
+	m, err := cmd.Flags().GetStringToInt("intmaptest")
+
+	assertEqual(t, 1, m["a"])
+	assertEqual(t, 2, m["b"])
+	assertNil(t, err)
+}
+
+type BindIntMapInvalidDefaultReturnsError struct {
+	IntMapTest map[string]int `config:"\"a=FOO\",The IntMap to test"`
+}
+
+func TestBindIntMapInvalidDefaultReturnsError(t *testing.T) {
+	cmd := &cobra.Command{}
+	err := Bind(BindIntMapInvalidDefaultReturnsError{}, cmd)
+
+	assertErrorIs(t, err, &parseError{})
+}
+
+// StringToInt64.
+type BindInt64MapSetsDefault struct {
+	Int64MapTest map[string]int64 `config:"default={\"a\":64};desc=The Int64Map to test"`
+}
+
+func TestBindInt64MapSetsDefault(t *testing.T) {
+	cmd := &cobra.Command{}
+	err := Bind(BindInt64MapSetsDefault{}, cmd)
+	assertNil(t, err)
+
+	m, err := cmd.Flags().GetStringToInt64("int64maptest")
+
+	assertEqual(t, int64(64), m["a"])
+	assertNil(t, err)
+}
+
+// DurationSlice.
+type BindDurationSliceSetsDefault struct {
+	DurationSliceTest []time.Duration `config:"\"[\"\"1s\"\",\"\"2m\"\"]\",The DurationSlice to test"`
+}
+
+func TestBindDurationSliceSetsDefault(t *testing.T) {
+	cmd := &cobra.Command{}
+	err := Bind(BindDurationSliceSetsDefault{}, cmd)
+	assertNil(t, err)
+
+	i, err := cmd.Flags().GetDurationSlice("durationslicetest")
+
+	assertSliceEqual(t, []time.Duration{time.Second, 2 * time.Minute}, i)
+	assertNil(t, err)
+}
+
+type BindDurationSliceInvalidDefaultReturnsError struct {
+	DurationSliceTest []time.Duration `config:"\"[\"\"FOO\"\"]\",The DurationSlice to test"`
+}
+
+func TestBindDurationSliceInvalidDefaultReturnsError(t *testing.T) {
+	cmd := &cobra.Command{}
+	err := Bind(BindDurationSliceInvalidDefaultReturnsError{}, cmd)
+
+	assertError(t, err)
+}
+
+// Int8Slice, Int16Slice, Uint16Slice, Uint32Slice and Uint64Slice.
+type BindSmallIntSlicesSetDefaults struct {
+	Int8Slice   []int8   `config:"\"[-1,2]\",The Int8Slice to test"`
+	Int16Slice  []int16  `config:"\"[300]\",The Int16Slice to test"`
+	Uint16Slice []uint16 `config:"\"[1,2]\",The Uint16Slice to test"`
+	Uint32Slice []uint32 `config:"\"[3]\",The Uint32Slice to test"`
+	Uint64Slice []uint64 `config:",The Uint64Slice to test"`
+}
+
+func TestBindSmallIntSlicesSetDefaults(t *testing.T) {
+	v := viper.New()
+	cmd := &cobra.Command{}
+	err := Bind(BindSmallIntSlicesSetDefaults{}, cmd, &Options{Viper: v})
+	assertNil(t, err)
+
+	assertEqual(t, "[-1,2]", cmd.Flags().Lookup("int8slice").Value.String())
+	assertEqual(t, "int8Slice", cmd.Flags().Lookup("int8slice").Value.Type())
+
+	err = cmd.ParseFlags([]string{"--uint64slice", "1,2", "--uint64slice", "18446744073709551615", "--int16slice", "-5"})
+	assertNil(t, err)
+
+	cfg := &BindSmallIntSlicesSetDefaults{}
+	err = Load(cfg, &Options{Viper: v})
+	assertNil(t, err)
+
+	assertSliceEqual(t, []int8{-1, 2}, cfg.Int8Slice)
+	assertSliceEqual(t, []int16{-5}, cfg.Int16Slice)
+	assertSliceEqual(t, []uint16{1, 2}, cfg.Uint16Slice)
+	assertSliceEqual(t, []uint32{3}, cfg.Uint32Slice)
+	assertSliceEqual(t, []uint64{1, 2, 18446744073709551615}, cfg.Uint64Slice)
+}
+
+type BindInt8SliceInvalidDefaultReturnsError struct {
+	Int8Slice []int8 `config:"\"[128]\",The Int8Slice to test"`
+}
+
+func TestBindInt8SliceInvalidDefaultReturnsError(t *testing.T) {
+	cmd := &cobra.Command{}
+	err := Bind(BindInt8SliceInvalidDefaultReturnsError{}, cmd)
+
+	assertErrorIs(t, err, &parseError{})
+}
+
+func TestBindUint16SliceInvalidFlagReturnsError(t *testing.T) {
+	cmd := &cobra.Command{}
+	err := Bind(BindSmallIntSlicesSetDefaults{}, cmd, &Options{Viper: viper.New()})
+	assertNil(t, err)
+
+	err = cmd.ParseFlags([]string{"--uint16slice", "65536"})
+	assertError(t, err)
+}
+
+// Bytes.
+type BindBytesSetsDefault struct {
+	Base64 []byte `config:"default=aGVsbG8=;desc=The bytes as base64"`
+	Hex    []byte `config:"default=68656c6c6f;desc=The bytes as hex;encoding=hex"`
+}
+
+func TestBindBytesSetsDefault(t *testing.T) {
+	v := viper.New()
+	cmd := &cobra.Command{}
+	err := Bind(BindBytesSetsDefault{}, cmd, &Options{Viper: v})
+	assertNil(t, err)
+
+	b, err := cmd.Flags().GetBytesBase64("base64")
+	assertNil(t, err)
+	assertEqual(t, "hello", string(b))
+
+	b, err = cmd.Flags().GetBytesHex("hex")
+	assertNil(t, err)
+	assertEqual(t, "hello", string(b))
+
+	err = cmd.ParseFlags([]string{"--hex", "776f726c64"})
+	assertNil(t, err)
+
+	cfg := &BindBytesSetsDefault{}
+	err = Load(cfg, &Options{Viper: v})
+	assertNil(t, err)
+
+	assertEqual(t, "hello", string(cfg.Base64))
+	assertEqual(t, "world", string(cfg.Hex))
+}
+
+type BindBytesInvalidDefaultReturnsError struct {
+	Hex []byte `config:"default=xyz;desc=The bytes as hex;encoding=hex"`
+}
+
+func TestBindBytesInvalidDefaultReturnsError(t *testing.T) {
+	cmd := &cobra.Command{}
+	err := Bind(BindBytesInvalidDefaultReturnsError{}, cmd)
+
+	assertErrorIs(t, err, &parseError{})
+}
+
+type BindBytesUnknownEncodingReturnsError struct {
+	Bytes []byte `config:"default=;desc=The bytes;encoding=base32"`
+}
+
+func TestBindBytesUnknownEncodingReturnsError(t *testing.T) {
+	cmd := &cobra.Command{}
+	err := Bind(BindBytesUnknownEncodingReturnsError{}, cmd)
+
+	assertErrorIs(t, err, &tagParseError{})
+}
+
 // NestedStruct.
 type BindNestedStructSetsDefaults struct {
 	BoolSlice   []bool                            `config:"\"[true,true,false,true]\",The NestedStruct to test"`
@@ -479,7 +920,7 @@ func TestBindIgnoresUnexportedFields(t *testing.T) {
 // Error on invalid type
 
 type BindInvalidTypeError struct {
-	Map map[string]string `config:",The map"`
+	Map map[string]bool `config:",The map"`
 }
 
 func TestBindInvalidTypeError(t *testing.T) {
@@ -589,6 +1030,80 @@ func TestBindErrorsOnInvalidTag(t *testing.T) {
 	assertErrorIs(t, err, &tagParseError{})
 }
 
+// Viper instance.
+type BindUsesViperInstance struct {
+	Shared string `config:"default,The string to bind"`
+}
+
+func TestBindUsesViperInstance(t *testing.T) {
+	first, second := viper.New(), viper.New()
+
+	cmd := &cobra.Command{}
+	err := Bind(BindUsesViperInstance{}, cmd, &Options{Viper: first})
+	assertNil(t, err)
+
+	other := &cobra.Command{}
+	err = Bind(BindUsesViperInstance{}, other, &Options{Viper: second})
+	assertNil(t, err)
+
+	err = cmd.ParseFlags([]string{"--shared", "first"})
+	assertNil(t, err)
+
+	assertEqual(t, "first", first.GetString("shared"))
+	assertEqual(t, "default", second.GetString("shared"))
+	assertEqual(t, false, viper.IsSet("shared"))
+}
+
+// Env.
+type BindEnvUsesPrefix struct {
+	Server BindEnvUsesPrefixServer `config:""`
+}
+
+type BindEnvUsesPrefixServer struct {
+	Port int `config:"8080,The port to listen on"`
+}
+
+func TestBindEnvUsesPrefix(t *testing.T) {
+	t.Setenv("MYAPP_SERVER_PORT", "9090")
+
+	v := viper.New()
+	cmd := &cobra.Command{}
+	err := Bind(BindEnvUsesPrefix{}, cmd, &Options{Viper: v, EnvPrefix: "MYAPP", Separator: "-"})
+	assertNil(t, err)
+
+	assertEqual(t, 9090, v.GetInt("server-port"))
+	assertEqual(t, "The port to listen on (env: MYAPP_SERVER_PORT)", cmd.Flags().Lookup("server-port").Usage)
+}
+
+type BindEnvUsesTag struct {
+	Token string `config:",The token to use,false,,API_TOKEN"`
+}
+
+func TestBindEnvUsesTag(t *testing.T) {
+	t.Setenv("API_TOKEN", "secret")
+
+	v := viper.New()
+	cmd := &cobra.Command{}
+	err := Bind(BindEnvUsesTag{}, cmd, &Options{Viper: v})
+	assertNil(t, err)
+
+	assertEqual(t, "secret", v.GetString("token"))
+}
+
+func TestBindEnvFlagTakesPrecedence(t *testing.T) {
+	t.Setenv("MYAPP_SERVER_PORT", "9090")
+
+	v := viper.New()
+	cmd := &cobra.Command{}
+	err := Bind(BindEnvUsesPrefix{}, cmd, &Options{Viper: v, EnvPrefix: "MYAPP"})
+	assertNil(t, err)
+
+	err = cmd.ParseFlags([]string{"--server.port", "7070"})
+	assertNil(t, err)
+
+	assertEqual(t, 7070, v.GetInt("server.port"))
+}
+
 func assertNil(t *testing.T, val any) {
 	if val != nil {
 		fmt.Printf("expected nil but got %v\n", val)
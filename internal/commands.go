@@ -0,0 +1,143 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// addConfigCommands adds a `config` command to cmd, with sub commands for working with
+// the bound configuration.
+func (b *Binder) addConfigCommands(cmd *cobra.Command) error {
+	c := child(cmd, &cobra.Command{Use: "config", Short: "Work with the configuration"})
+
+	if !hasChild(c, "print") {
+		err := b.addPrintCommand(c)
+		if err != nil {
+			return err
+		}
+	}
+
+	if !hasChild(c, "init") {
+		err := b.addInitCommand(c)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (b *Binder) addPrintCommand(parent *cobra.Command) error {
+	format := string(FormatYAML)
+	c := &cobra.Command{
+		Use:   "print",
+		Short: "Print the effective configuration",
+		Args:  cobra.NoArgs,
+		RunE: func(c *cobra.Command, _ []string) error {
+			return Dump(c.OutOrStdout(), Format(format), c)
+		},
+	}
+
+	c.Flags().StringVarP(&format, "format", "f", format, "The format to print the configuration in")
+	parent.AddCommand(c)
+
+	return b.restrict(c, c.Flags().Lookup("format"), formats)
+}
+
+func (b *Binder) addInitCommand(parent *cobra.Command) error {
+	format, force := "", false
+	c := &cobra.Command{
+		Use:   "init [file]",
+		Short: "Write a sample config file containing the default of every setting",
+		Long: "Write a sample config file containing the default of every setting, along with its description.\n" +
+			"The file is written to stdout if no path is given. Otherwise the format is taken from the extension of the path unless --format is set.",
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(c *cobra.Command, args []string) error {
+			f := Format(format)
+			if f == "" && len(args) == 1 {
+				f = formatOf(args[0])
+			} else if f == "" {
+				f = FormatYAML
+			}
+
+			obj := b.obj
+			if obj == nil {
+				obj = reflect.New(b.typ).Interface()
+			}
+
+			data, err := GenerateSample(obj, f, b.opts)
+			if err != nil {
+				return err
+			}
+
+			if len(args) == 0 {
+				_, err = c.OutOrStdout().Write(data)
+				return err
+			}
+
+			mode := os.O_WRONLY | os.O_CREATE | os.O_EXCL
+			if force {
+				mode = os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+			}
+
+			file, err := os.OpenFile(args[0], mode, 0o644)
+			if err != nil {
+				return err
+			}
+
+			_, err = file.Write(data)
+			if err != nil {
+				file.Close()
+				return err
+			}
+
+			return file.Close()
+		},
+	}
+
+	c.Flags().StringVarP(&format, "format", "f", "", "The format to write the file in, taken from the file extension by default")
+	c.Flags().BoolVar(&force, "force", false, "Overwrite the file if it already exists")
+	parent.AddCommand(c)
+
+	return b.restrict(c, c.Flags().Lookup("format"), formats)
+}
+
+// formatOf returns the format of a config file based on its extension, defaulting to YAML.
+func formatOf(path string) Format {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return FormatJSON
+	case ".toml":
+		return FormatTOML
+	case ".env":
+		return FormatEnv
+	}
+
+	return FormatYAML
+}
+
+// child returns the sub command of cmd with the same name as c, adding c if there is none.
+func child(cmd *cobra.Command, c *cobra.Command) *cobra.Command {
+	for _, existing := range cmd.Commands() {
+		if existing.Name() == c.Name() {
+			return existing
+		}
+	}
+
+	cmd.AddCommand(c)
+	return c
+}
+
+func hasChild(cmd *cobra.Command, name string) bool {
+	for _, c := range cmd.Commands() {
+		if c.Name() == name {
+			return true
+		}
+	}
+
+	return false
+}
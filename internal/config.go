@@ -0,0 +1,315 @@
+package internal
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// readConfig reads the config file named by the config file field. If that field is
+// empty, the search paths are checked for a file named after the application instead,
+// and it is not an error for none to be found.
+func (b *Binder) readConfig(cmd *cobra.Command) error {
+	v := b.viper()
+
+	path := ""
+	if b.configKey != "" {
+		path = v.GetString(b.configKey)
+	}
+
+	if len(b.opts.ConfigLayers) > 0 {
+		return b.mergeLayers(path)
+	}
+
+	if path != "" {
+		v.SetConfigFile(path)
+		err := v.ReadInConfig()
+		if err != nil {
+			return NewConfigError(path, b.configKey, err)
+		}
+
+		return nil
+	}
+
+	name := b.configName(cmd)
+	v.SetConfigName(name)
+	for _, p := range b.configPaths(name) {
+		v.AddConfigPath(p)
+	}
+
+	err := v.ReadInConfig()
+	if errors.As(err, &viper.ConfigFileNotFoundError{}) {
+		return nil
+	} else if err != nil {
+		return NewConfigError(v.ConfigFileUsed(), b.configKey, err)
+	}
+
+	return nil
+}
+
+// configName returns the name, without extension, of the config file to search for.
+func (b *Binder) configName(cmd *cobra.Command) string {
+	if b.opts.ConfigName != "" {
+		return b.opts.ConfigName
+	}
+
+	return cmd.Root().Name()
+}
+
+// configPaths returns the directories to search for a config file, defaulting to
+// `$XDG_CONFIG_HOME/<name>`, `/etc/<name>` and the working directory.
+func (b *Binder) configPaths(name string) []string {
+	if b.opts.ConfigPaths != nil {
+		return b.opts.ConfigPaths
+	}
+
+	paths := []string{}
+	if dir, err := os.UserConfigDir(); err == nil {
+		paths = append(paths, filepath.Join(dir, name))
+	}
+
+	return append(paths, filepath.Join("/etc", name), ".")
+}
+
+// profilePlaceholder is replaced in ConfigLayers with the selected profile.
+const profilePlaceholder = "{profile}"
+
+// Layer is a config file that has been merged into the configuration, along with the
+// bound keys that it set.
+type Layer struct {
+	Path string
+	Keys []string
+}
+
+// mergeLayers merges each of the config layers in order, followed by the config file
+// named by the config file field if there is one. Layers that do not exist are skipped,
+// except for profile layers when a profile has been selected.
+//
+// The layers are merged into a viper instance of their own, which then replaces the
+// config of the bound instance in one go, so that it is never seen partly merged.
+func (b *Binder) mergeLayers(path string) error {
+	v := b.viper()
+	profile := ""
+	if b.hasProfiles() {
+		profile = v.GetString(b.profileFlag())
+	}
+
+	paths := []string{}
+	for _, l := range b.opts.ConfigLayers {
+		required := strings.Contains(l, profilePlaceholder)
+		if required && profile == "" {
+			continue
+		}
+
+		l = strings.ReplaceAll(l, profilePlaceholder, profile)
+		if _, err := os.Stat(l); !required && errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+
+		paths = append(paths, l)
+	}
+
+	if path != "" {
+		paths = append(paths, path)
+	}
+
+	if len(paths) == 0 {
+		b.setLayers([]*Layer{})
+		return nil
+	}
+
+	merged := viper.New()
+	layers := []*Layer{}
+	for _, p := range paths {
+		layer, err := b.mergeLayer(merged, p)
+		if err != nil {
+			return err
+		}
+
+		layers = append(layers, layer)
+	}
+
+	// The merged instance holds nothing but the config, so writing it out in the format of
+	// the last layer and reading it back gives the same config.
+	buf := &bytes.Buffer{}
+	err := merged.WriteConfigTo(buf)
+	if err != nil {
+		return NewConfigError(paths[len(paths)-1], b.configKey, err)
+	}
+
+	v.SetConfigFile(paths[len(paths)-1])
+	err = v.ReadConfig(buf)
+	if err != nil {
+		return NewConfigError(paths[len(paths)-1], b.configKey, err)
+	}
+
+	b.setLayers(layers)
+	return nil
+}
+
+// mergeLayer merges a config file into v, returning the layer with the bound keys it set.
+func (b *Binder) mergeLayer(v *viper.Viper, path string) (*Layer, error) {
+	v.SetConfigFile(path)
+	err := v.MergeInConfig()
+	if err != nil {
+		return nil, NewConfigError(path, b.configKey, err)
+	}
+
+	// Read the layer on its own as well, to find out which keys it set.
+	lv := viper.New()
+	lv.SetConfigFile(path)
+	err = lv.ReadInConfig()
+	if err != nil {
+		return nil, NewConfigError(path, b.configKey, err)
+	}
+
+	layer := &Layer{Path: path, Keys: []string{}}
+	for _, f := range b.fields {
+		if lv.InConfig(f.Key) {
+			layer.Keys = append(layer.Keys, f.Key)
+		}
+	}
+
+	return layer, nil
+}
+
+// setLayers records the layers that were merged. They can be replaced by Watch at any
+// time, so are guarded by a lock.
+func (b *Binder) setLayers(layers []*Layer) {
+	b.layersMu.Lock()
+	defer b.layersMu.Unlock()
+
+	b.layers = layers
+}
+
+func (b *Binder) mergedLayers() []*Layer {
+	b.layersMu.RLock()
+	defer b.layersMu.RUnlock()
+
+	return b.layers
+}
+
+// layerFiles returns the paths of every config layer that mergeLayers would read, whether
+// or not they exist, followed by the config file named by the config file field.
+func (b *Binder) layerFiles() []string {
+	v := b.viper()
+	profile := ""
+	if b.hasProfiles() {
+		profile = v.GetString(b.profileFlag())
+	}
+
+	files := []string{}
+	for _, l := range b.opts.ConfigLayers {
+		if strings.Contains(l, profilePlaceholder) && profile == "" {
+			continue
+		}
+
+		files = append(files, strings.ReplaceAll(l, profilePlaceholder, profile))
+	}
+
+	if b.configKey != "" && v.GetString(b.configKey) != "" {
+		files = append(files, v.GetString(b.configKey))
+	}
+
+	return files
+}
+
+// isConfigFile reports whether the field with key n holds the path of a config file.
+func (b *Binder) isConfigFile(n string, t *Tag) bool {
+	return t.ConfigFile || (b.opts.ConfigFlag != "" && n == b.opts.ConfigFlag)
+}
+
+// hasProfiles reports whether any of the config layers depend on the selected profile.
+func (b *Binder) hasProfiles() bool {
+	for _, l := range b.opts.ConfigLayers {
+		if strings.Contains(l, profilePlaceholder) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// profileFlag returns the name of the flag used to select a profile.
+func (b *Binder) profileFlag() string {
+	if b.opts.ProfileFlag != "" {
+		return b.opts.ProfileFlag
+	}
+
+	return "profile"
+}
+
+// bindProfile registers the persistent flag used to select a profile.
+func (b *Binder) bindProfile(cmd *cobra.Command) error {
+	n := b.profileFlag()
+	t := &Tag{Description: "The config profile to use"}
+
+	env := b.env(n, t)
+	if env != "" {
+		t.Description = fmt.Sprintf("%s (env: %s)", t.Description, env)
+	}
+
+	f := cmd.PersistentFlags()
+	f.String(n, "", t.Description)
+
+	err := b.viper().BindPFlag(n, f.Lookup(n))
+	if err != nil {
+		return NewBindError(reflect.String, n, err)
+	}
+
+	if env != "" {
+		err = b.viper().BindEnv(n, env)
+		if err != nil {
+			return NewBindError(reflect.String, n, err)
+		}
+	}
+
+	return nil
+}
+
+// LayerReport returns the config layer that supplied the final value of each key bound
+// for a command, keyed by the bound key. Keys not set by any layer, or overridden by a
+// flag or env var, are omitted.
+func LayerReport(cmd *cobra.Command) map[string]string {
+	report := map[string]string{}
+
+	all := binders(cmd)
+	for i := len(all) - 1; i >= 0; i-- {
+		for _, l := range all[i].mergedLayers() {
+			for _, k := range l.Keys {
+				report[k] = l.Path
+			}
+		}
+	}
+
+	for _, b := range all {
+		for _, f := range b.fields {
+			if f.overridden() {
+				delete(report, f.Key)
+			}
+		}
+	}
+
+	return report
+}
+
+// overridden reports whether a flag or env var was set for a field, taking precedence
+// over any config file.
+func (f *Field) overridden() bool {
+	if f.Flag != nil && f.Flag.Changed {
+		return true
+	}
+
+	if f.Env == "" {
+		return false
+	}
+
+	return envSet(f.Env) || (f.Tag.Secret && envSet(secretFileEnv(f.Env)))
+}
@@ -0,0 +1,239 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// Config file.
+type ConfigReadsFile struct {
+	Config string `config:"default=;desc=The config file to read;configfile;short=c"`
+	Port   int    `config:"8080,The port to listen on"`
+	Name   string `config:"mamba,The name to use"`
+}
+
+func writeConfig(t *testing.T, dir, name, contents string) string {
+	path := filepath.Join(dir, name)
+	err := os.WriteFile(path, []byte(contents), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func TestConfigReadsFile(t *testing.T) {
+	path := writeConfig(t, t.TempDir(), "app.yaml", "port: 9090\n")
+
+	opts := &Options{Viper: viper.New()}
+	cfg := &ConfigReadsFile{}
+	cmd := &cobra.Command{Use: "app", RunE: func(*cobra.Command, []string) error { return Load(cfg, opts) }}
+	err := Bind(ConfigReadsFile{}, cmd, opts)
+	assertNil(t, err)
+
+	cmd.SetArgs([]string{"-c", path, "--name", "viper"})
+	err = cmd.Execute()
+	assertNil(t, err)
+
+	assertEqual(t, 9090, cfg.Port)
+	assertEqual(t, "viper", cfg.Name)
+}
+
+type ConfigSearchesPaths struct {
+	ConfigFile string `config:",The config file to read"`
+	Port       int    `config:"8080,The port to listen on"`
+}
+
+func TestConfigSearchesPaths(t *testing.T) {
+	dir := t.TempDir()
+	writeConfig(t, dir, "snakes.toml", "port = 7070\n")
+
+	opts := &Options{Viper: viper.New(), ConfigFlag: "configfile", ConfigName: "snakes", ConfigPaths: []string{t.TempDir(), dir}}
+	cfg := &ConfigSearchesPaths{}
+	cmd := &cobra.Command{Use: "app", RunE: func(*cobra.Command, []string) error { return Load(cfg, opts) }}
+	err := Bind(ConfigSearchesPaths{}, cmd, opts)
+	assertNil(t, err)
+
+	cmd.SetArgs([]string{})
+	err = cmd.Execute()
+	assertNil(t, err)
+
+	assertEqual(t, 7070, cfg.Port)
+}
+
+func TestConfigSearchIgnoresMissingFile(t *testing.T) {
+	opts := &Options{Viper: viper.New(), ConfigFlag: "configfile", ConfigPaths: []string{t.TempDir()}}
+	cfg := &ConfigSearchesPaths{}
+	cmd := &cobra.Command{Use: "app", RunE: func(*cobra.Command, []string) error { return Load(cfg, opts) }}
+	err := Bind(ConfigSearchesPaths{}, cmd, opts)
+	assertNil(t, err)
+
+	cmd.SetArgs([]string{})
+	err = cmd.Execute()
+	assertNil(t, err)
+
+	assertEqual(t, 8080, cfg.Port)
+}
+
+func TestConfigErrorsOnMissingFile(t *testing.T) {
+	opts := &Options{Viper: viper.New()}
+	cmd := &cobra.Command{Use: "app", Run: func(*cobra.Command, []string) {}}
+	err := Bind(ConfigReadsFile{}, cmd, opts)
+	assertNil(t, err)
+
+	cmd.SilenceErrors, cmd.SilenceUsage = true, true
+	cmd.SetArgs([]string{"--config", filepath.Join(t.TempDir(), "missing.json")})
+	err = cmd.Execute()
+	assertErrorIs(t, err, &configError{})
+}
+
+func TestConfigKeepsExistingHook(t *testing.T) {
+	path := writeConfig(t, t.TempDir(), "app.json", `{"port": 6060}`)
+
+	opts := &Options{Viper: viper.New()}
+	port := 0
+	cmd := &cobra.Command{
+		Use:              "app",
+		PersistentPreRun: func(*cobra.Command, []string) { port = opts.Viper.GetInt("port") },
+		Run:              func(*cobra.Command, []string) {},
+	}
+	err := Bind(ConfigReadsFile{}, cmd, opts)
+	assertNil(t, err)
+
+	cmd.SetArgs([]string{"--config", path})
+	err = cmd.Execute()
+	assertNil(t, err)
+
+	assertEqual(t, 6060, port)
+}
+
+func TestConfigReadFromSubCommandHook(t *testing.T) {
+	path := writeConfig(t, t.TempDir(), "app.yaml", "port: 9999\n")
+
+	for _, read := range []bool{false, true} {
+		opts := &Options{Viper: viper.New(), Persistent: true}
+		cfg := &ConfigReadsFile{}
+		root := &cobra.Command{Use: "app"}
+		serve := &cobra.Command{
+			Use: "serve",
+			PersistentPreRunE: func(cmd *cobra.Command, _ []string) error {
+				if read {
+					return ReadConfig(cmd)
+				}
+
+				return nil
+			},
+			RunE: func(*cobra.Command, []string) error { return Load(cfg, opts) },
+		}
+		root.AddCommand(serve)
+
+		err := Bind(ConfigReadsFile{}, root, opts)
+		assertNil(t, err)
+
+		root.SetArgs([]string{"serve", "--config", path})
+		err = root.Execute()
+		assertNil(t, err)
+
+		// Cobra skips the hook on the root once serve has its own.
+		if read {
+			assertEqual(t, 9999, cfg.Port)
+		} else {
+			assertEqual(t, 8080, cfg.Port)
+		}
+	}
+}
+
+// Layers.
+type ConfigMergesLayers struct {
+	Config string                   `config:"default=;desc=The config file to read;configfile"`
+	Server ConfigMergesLayersServer `config:""`
+	Name   string                   `config:"mamba,The name to use"`
+}
+
+type ConfigMergesLayersServer struct {
+	Host string `config:"localhost,The host to listen on"`
+	Port int    `config:"8080,The port to listen on"`
+}
+
+func TestConfigMergesLayers(t *testing.T) {
+	dir := t.TempDir()
+	base := writeConfig(t, dir, "base.yaml", "server:\n  host: base\n  port: 1000\nname: base\n")
+	err := os.Mkdir(filepath.Join(dir, "profiles"), 0o700)
+	assertNil(t, err)
+	prod := writeConfig(t, dir, "profiles/prod.yaml", "server:\n  port: 2000\n")
+	local := writeConfig(t, dir, "local.json", `{"name": "local"}`)
+
+	opts := &Options{
+		Viper:        viper.New(),
+		ConfigLayers: []string{base, filepath.Join(dir, "profiles", "{profile}.yaml"), filepath.Join(dir, "missing.yaml")},
+	}
+	cfg := &ConfigMergesLayers{}
+	cmd := &cobra.Command{Use: "app", RunE: func(*cobra.Command, []string) error { return Load(cfg, opts) }}
+	err = Bind(ConfigMergesLayers{}, cmd, opts)
+	assertNil(t, err)
+
+	cmd.SetArgs([]string{"--profile", "prod", "--config", local})
+	err = cmd.Execute()
+	assertNil(t, err)
+
+	assertEqual(t, "base", cfg.Server.Host)
+	assertEqual(t, 2000, cfg.Server.Port)
+	assertEqual(t, "local", cfg.Name)
+
+	report := LayerReport(cmd)
+	assertEqual(t, base, report["server.host"])
+	assertEqual(t, prod, report["server.port"])
+	assertEqual(t, local, report["name"])
+}
+
+func TestLayerReportOmitsOverriddenKeys(t *testing.T) {
+	dir := t.TempDir()
+	base := writeConfig(t, dir, "base.yaml", "server:\n  host: base\n  port: 1000\nname: base\n")
+	t.Setenv("APP_NAME", "env")
+	t.Setenv("APP_SERVER_HOST", "")
+
+	opts := &Options{Viper: viper.New(), ConfigLayers: []string{base}, EnvPrefix: "APP"}
+	cmd := &cobra.Command{Use: "app", Run: func(*cobra.Command, []string) {}}
+	err := Bind(ConfigMergesLayers{}, cmd, opts)
+	assertNil(t, err)
+
+	cmd.SetArgs([]string{"--server.port", "2000"})
+	err = cmd.Execute()
+	assertNil(t, err)
+
+	report := LayerReport(cmd)
+	assertEqual(t, base, report["server.host"])
+	_, ok := report["server.port"]
+	assertEqual(t, false, ok)
+	_, ok = report["name"]
+	assertEqual(t, false, ok)
+}
+
+func TestConfigLayersWithoutProfile(t *testing.T) {
+	dir := t.TempDir()
+	base := writeConfig(t, dir, "base.yaml", "server:\n  port: 1000\n")
+
+	opts := &Options{
+		Viper:        viper.New(),
+		ConfigLayers: []string{base, filepath.Join(dir, "{profile}.yaml")},
+		ProfileFlag:  "env",
+	}
+	cfg := &ConfigMergesLayers{}
+	cmd := &cobra.Command{Use: "app", RunE: func(*cobra.Command, []string) error { return Load(cfg, opts) }}
+	err := Bind(ConfigMergesLayers{}, cmd, opts)
+	assertNil(t, err)
+
+	cmd.SetArgs([]string{})
+	err = cmd.Execute()
+	assertNil(t, err)
+	assertEqual(t, 1000, cfg.Server.Port)
+
+	cmd.SilenceErrors, cmd.SilenceUsage = true, true
+	cmd.SetArgs([]string{"--env", "missing"})
+	err = cmd.Execute()
+	assertErrorIs(t, err, &configError{})
+}
@@ -0,0 +1,46 @@
+package internal
+
+import (
+	"reflect"
+	"sync"
+
+	"github.com/spf13/pflag"
+)
+
+// Converter allows mamba to bind types it does not support natively.
+type Converter interface {
+	// ParseDefault parses the default from the config tag into a value of the type.
+	ParseDefault(s string) (any, error)
+
+	// NewValue returns a pflag.Value for the flag, holding the given default. The default
+	// is the zero value of the type when the tag does not supply one.
+	NewValue(def any) pflag.Value
+
+	// Decode converts the value resolved by viper, which may come from a flag, env var
+	// or config file, into a value of the type.
+	Decode(raw any) (any, error)
+}
+
+var convertersMu sync.RWMutex
+var converters = map[reflect.Type]Converter{}
+
+// RegisterType registers a converter for a type with every binder. Converters supplied
+// via Options.Converters take precedence over those registered here.
+func RegisterType(t reflect.Type, c Converter) {
+	convertersMu.Lock()
+	defer convertersMu.Unlock()
+
+	converters[t] = c
+}
+
+// converter returns the converter registered for a type, or nil if there is none.
+func (b *Binder) converter(t reflect.Type) Converter {
+	if c, ok := b.opts.Converters[t]; ok {
+		return c
+	}
+
+	convertersMu.RLock()
+	defer convertersMu.RUnlock()
+
+	return converters[t]
+}
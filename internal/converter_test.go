@@ -0,0 +1,135 @@
+package internal
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cast"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+type byteSize int64
+
+func parseByteSize(s string) (byteSize, error) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	mult := int64(1)
+	switch {
+	case strings.HasSuffix(s, "KB"):
+		mult, s = 1024, strings.TrimSuffix(s, "KB")
+	case strings.HasSuffix(s, "MB"):
+		mult, s = 1024*1024, strings.TrimSuffix(s, "MB")
+	}
+
+	i, err := strconv.ParseInt(s, 10, 64)
+	return byteSize(i * mult), err
+}
+
+type byteSizeValue struct{ b *byteSize }
+
+func (v byteSizeValue) String() string { return fmt.Sprint(int64(*v.b)) }
+func (v byteSizeValue) Type() string   { return "bytesize" }
+func (v byteSizeValue) Set(s string) (err error) {
+	*v.b, err = parseByteSize(s)
+	return err
+}
+
+type byteSizeConverter struct{}
+
+func (byteSizeConverter) ParseDefault(s string) (any, error) { return parseByteSize(s) }
+func (byteSizeConverter) NewValue(def any) pflag.Value {
+	b := def.(byteSize)
+	return byteSizeValue{&b}
+}
+func (byteSizeConverter) Decode(raw any) (any, error) {
+	s, err := cast.ToStringE(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseByteSize(s)
+}
+
+// Converter.
+type BindConverterSetsDefault struct {
+	MaxBody byteSize `config:"2KB,The maximum body size"`
+}
+
+func TestBindConverterSetsDefault(t *testing.T) {
+	opts := &Options{Viper: viper.New(), Converters: map[reflect.Type]Converter{reflect.TypeOf(byteSize(0)): byteSizeConverter{}}}
+	cmd := &cobra.Command{}
+	err := Bind(BindConverterSetsDefault{}, cmd, opts)
+	assertNil(t, err)
+
+	assertEqual(t, "2048", cmd.Flags().Lookup("maxbody").DefValue)
+
+	err = cmd.ParseFlags([]string{"--maxbody", "1MB"})
+	assertNil(t, err)
+
+	cfg := &BindConverterSetsDefault{}
+	err = Load(cfg, opts)
+	assertNil(t, err)
+	assertEqual(t, byteSize(1024*1024), cfg.MaxBody)
+}
+
+type BindConverterInvalidDefaultReturnsError struct {
+	MaxBody byteSize `config:"lots,The maximum body size"`
+}
+
+func TestBindConverterInvalidDefaultReturnsError(t *testing.T) {
+	opts := &Options{Converters: map[reflect.Type]Converter{reflect.TypeOf(byteSize(0)): byteSizeConverter{}}}
+	cmd := &cobra.Command{}
+	err := Bind(BindConverterInvalidDefaultReturnsError{}, cmd, opts)
+
+	assertErrorIs(t, err, &parseError{})
+}
+
+type percent float64
+
+type percentConverter struct{}
+
+func (percentConverter) ParseDefault(s string) (any, error) {
+	f, err := strconv.ParseFloat(strings.TrimSuffix(s, "%"), 64)
+	return percent(f / 100), err
+}
+func (percentConverter) NewValue(def any) pflag.Value {
+	p := def.(percent)
+	return (*percentValue)(&p)
+}
+func (percentConverter) Decode(raw any) (any, error) {
+	return percentConverter{}.ParseDefault(cast.ToString(raw))
+}
+
+type percentValue percent
+
+func (p *percentValue) String() string { return fmt.Sprintf("%v%%", float64(*p)*100) }
+func (p *percentValue) Type() string   { return "percent" }
+func (p *percentValue) Set(s string) error {
+	v, err := percentConverter{}.ParseDefault(s)
+	if err == nil {
+		*p = percentValue(v.(percent))
+	}
+	return err
+}
+
+type BindRegisteredConverter struct {
+	Threshold percent `config:"50%,The alert threshold"`
+}
+
+func TestBindRegisteredConverter(t *testing.T) {
+	RegisterType(reflect.TypeOf(percent(0)), percentConverter{})
+
+	opts := &Options{Viper: viper.New()}
+	cmd := &cobra.Command{}
+	err := Bind(BindRegisteredConverter{}, cmd, opts)
+	assertNil(t, err)
+
+	cfg := &BindRegisteredConverter{}
+	err = Load(cfg, opts)
+	assertNil(t, err)
+	assertEqual(t, percent(0.5), cfg.Threshold)
+}
@@ -0,0 +1,171 @@
+package internal
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// docGroup is the fields of a single nested struct, or of the top level when name is
+// empty, in the order they were bound.
+type docGroup struct {
+	name   string
+	fields []*docField
+}
+
+type docField struct {
+	*Field
+	persistent bool
+}
+
+// docGroups returns the fields bound to a command and its parents, grouped by the struct
+// they are nested in.
+func docGroups(cmd *cobra.Command) []*docGroup {
+	groups := []*docGroup{}
+	byName := map[string]*docGroup{}
+	for _, b := range binders(cmd) {
+		for _, f := range b.fields {
+			name := ""
+			if i := strings.LastIndex(f.Key, b.opts.Separator); i >= 0 {
+				name = f.Key[:i]
+			}
+
+			g, ok := byName[name]
+			if !ok {
+				g = &docGroup{name: name}
+				byName[name] = g
+				groups = append(groups, g)
+			}
+
+			g.fields = append(g.fields, &docField{f, f.Tag.Persistent || b.opts.Persistent})
+		}
+	}
+
+	return groups
+}
+
+func (f *docField) flag() string {
+	if f.Flag == nil {
+		return ""
+	}
+
+	if f.Flag.Shorthand != "" {
+		return fmt.Sprintf("--%s, -%s", f.Flag.Name, f.Flag.Shorthand)
+	}
+
+	return fmt.Sprintf("--%s", f.Flag.Name)
+}
+
+func (f *docField) def() string {
+	if f.Flag == nil {
+		return ""
+	}
+
+	return f.Flag.DefValue
+}
+
+// GenMarkdown writes a reference of the configuration bound to a command and its parents
+// as Markdown, with a table of the keys of each nested struct giving their flag, env var,
+// type, default, persistence and description.
+func GenMarkdown(w io.Writer, cmd *cobra.Command) error {
+	out := &strings.Builder{}
+	fmt.Fprintf(out, "# %s configuration\n", cmd.CommandPath())
+
+	for _, g := range docGroups(cmd) {
+		if g.name != "" {
+			fmt.Fprintf(out, "\n## %s\n", g.name)
+		}
+
+		out.WriteString("\n| Key | Flag | Env | Type | Default | Persistent | Description |\n")
+		out.WriteString("| --- | --- | --- | --- | --- | --- | --- |\n")
+		for _, f := range g.fields {
+			fmt.Fprintf(out, "| %s | %s | %s | %s | %s | %s | %s |\n",
+				mdCode(f.Key), mdCode(f.flag()), mdCode(f.Env), mdCode(f.Type.String()),
+				mdCode(f.def()), yesNo(f.persistent), mdEscape(f.Description))
+		}
+	}
+
+	_, err := io.WriteString(w, out.String())
+	return err
+}
+
+// GenMan writes a reference of the configuration bound to a command and its parents as a
+// man page, with a section for the keys of each nested struct.
+func GenMan(w io.Writer, cmd *cobra.Command) error {
+	out := &strings.Builder{}
+	name := strings.ReplaceAll(cmd.CommandPath(), " ", "-")
+	fmt.Fprintf(out, ".TH \"%s\" \"5\" \"\" \"\" \"Configuration\"\n", roffEscape(strings.ToUpper(name)))
+	fmt.Fprintf(out, ".SH NAME\n%s \\- configuration reference\n", roffEscape(name))
+
+	for _, g := range docGroups(cmd) {
+		if g.name == "" {
+			out.WriteString(".SH CONFIGURATION\n")
+		} else {
+			fmt.Fprintf(out, ".SH %s\n", roffEscape(strings.ToUpper(g.name)))
+		}
+
+		for _, f := range g.fields {
+			fmt.Fprintf(out, ".TP\n\\fB%s\\fP (%s)\n", roffEscape(f.Key), roffEscape(f.Type.String()))
+			if f.Description != "" {
+				fmt.Fprintf(out, "%s\n", roffEscape(f.Description))
+			}
+
+			details := []string{}
+			if flag := f.flag(); flag != "" {
+				details = append(details, fmt.Sprintf("Flag: \\fB%s\\fP.", roffEscape(flag)))
+			}
+
+			if f.Env != "" {
+				details = append(details, fmt.Sprintf("Env: \\fB%s\\fP.", roffEscape(f.Env)))
+			}
+
+			if def := f.def(); def != "" {
+				details = append(details, fmt.Sprintf("Default: %s.", roffEscape(def)))
+			}
+
+			if f.persistent {
+				details = append(details, "Persistent.")
+			}
+
+			if len(details) > 0 {
+				fmt.Fprintf(out, ".br\n%s\n", strings.Join(details, " "))
+			}
+		}
+	}
+
+	_, err := io.WriteString(w, out.String())
+	return err
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+
+	return "no"
+}
+
+func mdEscape(s string) string {
+	return strings.ReplaceAll(s, "|", "\\|")
+}
+
+func mdCode(s string) string {
+	if s == "" {
+		return ""
+	}
+
+	return fmt.Sprintf("`%s`", mdEscape(s))
+}
+
+// roffEscape escapes text for a man page, so that backslashes and dashes are printed
+// literally and lines cannot be mistaken for requests.
+func roffEscape(s string) string {
+	s = strings.NewReplacer("\\", "\\e", "-", "\\-").Replace(s)
+	if strings.HasPrefix(s, ".") || strings.HasPrefix(s, "'") {
+		s = "\\&" + s
+	}
+
+	return s
+}
@@ -0,0 +1,57 @@
+package internal
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// Docs.
+type DocsConfig struct {
+	Name   string     `config:"default=mamba;desc=The name | alias;short=n;persistent"`
+	Level  string     `config:"default=info;desc=The log level;oneof=debug|info"`
+	Server DocsServer `config:""`
+}
+
+type DocsServer struct {
+	Port int `config:"default=8080;desc=The port to listen on;env=PORT"`
+}
+
+func TestGenMarkdown(t *testing.T) {
+	cmd := &cobra.Command{Use: "app"}
+	err := Bind(DocsConfig{}, cmd, &Options{Viper: viper.New()})
+	assertNil(t, err)
+
+	out := &bytes.Buffer{}
+	err = GenMarkdown(out, cmd)
+	assertNil(t, err)
+
+	expected := "# app configuration\n\n" +
+		"| Key | Flag | Env | Type | Default | Persistent | Description |\n" +
+		"| --- | --- | --- | --- | --- | --- | --- |\n" +
+		"| `name` | `--name, -n` |  | `string` | `mamba` | yes | The name \\| alias |\n" +
+		"| `level` | `--level` |  | `string` | `info` | no | The log level |\n" +
+		"\n## server\n\n" +
+		"| Key | Flag | Env | Type | Default | Persistent | Description |\n" +
+		"| --- | --- | --- | --- | --- | --- | --- |\n" +
+		"| `server.port` | `--server.port` | `PORT` | `int` | `8080` | no | The port to listen on |\n"
+	assertEqual(t, expected, out.String())
+}
+
+func TestGenMan(t *testing.T) {
+	root := &cobra.Command{Use: "app"}
+	sub := &cobra.Command{Use: "serve"}
+	root.AddCommand(sub)
+	err := Bind(DocsConfig{}, root, &Options{Viper: viper.New()})
+	assertNil(t, err)
+
+	out := &bytes.Buffer{}
+	err = GenMan(out, sub)
+	assertNil(t, err)
+
+	assertEqual(t, true, strings.HasPrefix(out.String(), ".TH \"APP\\-SERVE\" \"5\""))
+	assertEqual(t, true, strings.Contains(out.String(), ".SH SERVER\n.TP\n\\fBserver.port\\fP (int)\nThe port to listen on\n.br\nFlag: \\fB\\-\\-server.port\\fP. Env: \\fBPORT\\fP. Default: 8080.\n"))
+}
@@ -0,0 +1,225 @@
+package internal
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pelletier/go-toml/v2"
+	"github.com/spf13/cobra"
+	"go.yaml.in/yaml/v3"
+)
+
+// Format is an output format for dumping or generating config.
+type Format string
+
+const (
+	FormatYAML Format = "yaml"
+	FormatJSON Format = "json"
+	FormatTOML Format = "toml"
+	FormatEnv  Format = "env"
+)
+
+var formats = []string{string(FormatYAML), string(FormatJSON), string(FormatTOML), string(FormatEnv)}
+
+// Dump writes the final value of every key bound to a command or its parents to w in the
+// given format. Keys are nested using the separator, so `server.port` is written as a
+// `port` key within a `server` object.
+func Dump(w io.Writer, format Format, cmd *cobra.Command) error {
+	tree := map[string]any{}
+	env := [][2]string{}
+	for _, b := range binders(cmd) {
+		for _, f := range b.fields {
+			v, err := b.resolve(f)
+			if err != nil {
+				return err
+			}
+
+			value := b.plain(f, v)
+			if f.Tag.Secret {
+				value = redacted
+			}
+			b.nest(tree, f.Key, value)
+			env = append(env, [2]string{b.envName(f), envValue(value)})
+		}
+	}
+
+	if format == FormatEnv {
+		sort.Slice(env, func(i, j int) bool { return env[i][0] < env[j][0] })
+		for _, e := range env {
+			_, err := fmt.Fprintf(w, "%s=%s\n", e[0], e[1])
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	return encode(w, format, tree)
+}
+
+// encode writes a nested map to w in the given format.
+func encode(w io.Writer, format Format, tree map[string]any) error {
+	switch format {
+	case FormatYAML:
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		err := enc.Encode(tree)
+		if err != nil {
+			return err
+		}
+
+		return enc.Close()
+	case FormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(tree)
+	case FormatTOML:
+		return toml.NewEncoder(w).Encode(tree)
+	}
+
+	return fmt.Errorf("unsupported format \"%s\"", format)
+}
+
+// nest sets value in tree at the path given by splitting the key on the separator.
+func (b *Binder) nest(tree map[string]any, key string, value any) {
+	parts := strings.Split(key, b.opts.Separator)
+	for _, p := range parts[:len(parts)-1] {
+		child, ok := tree[p].(map[string]any)
+		if !ok {
+			child = map[string]any{}
+			tree[p] = child
+		}
+
+		tree = child
+	}
+
+	tree[parts[len(parts)-1]] = value
+}
+
+// plain converts a field value into the basic types understood by the encoders, using
+// the textual form of durations, times and custom types.
+func (b *Binder) plain(f *Field, v reflect.Value) any {
+	if c := b.converter(v.Type()); c != nil {
+		return c.NewValue(v.Interface()).String()
+	}
+
+	switch v.Type() {
+	case durationType:
+		return v.Interface().(time.Duration).String()
+	case timeType:
+		return v.Interface().(time.Time).Format(b.layout(f.Tag))
+	}
+
+	if isCustom(v.Type()) {
+		p := v
+		if v.Kind() != reflect.Ptr {
+			p = reflect.New(v.Type())
+			p.Elem().Set(v)
+		} else if v.IsNil() {
+			return ""
+		}
+
+		switch m := p.Interface().(type) {
+		case encoding.TextMarshaler:
+			s, err := m.MarshalText()
+			if err == nil {
+				return string(s)
+			}
+		case fmt.Stringer:
+			return m.String()
+		}
+	}
+
+	if isBytes(v.Type()) {
+		return encodeBytes(v.Bytes(), f.Tag.Encoding)
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return nil
+		}
+
+		return b.plain(f, v.Elem())
+	case reflect.Struct:
+		items := map[string]any{}
+		b.walkFields("", v, func(n string, t *Tag, field reflect.StructField, v reflect.Value) error {
+			b.nest(items, n, b.plain(&Field{Key: n, Type: field.Type, Tag: t}, v))
+			return nil
+		})
+
+		return items
+	case reflect.Slice, reflect.Array:
+		items := make([]any, v.Len())
+		for i := range items {
+			items[i] = b.plain(f, v.Index(i))
+		}
+
+		return items
+	case reflect.Map:
+		items := make(map[string]any, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			items[fmt.Sprint(iter.Key().Interface())] = b.plain(f, iter.Value())
+		}
+
+		return items
+	}
+
+	return v.Interface()
+}
+
+// envName returns the env var a field is bound to, or the name it would have with no
+// prefix if it is not bound to one.
+func (b *Binder) envName(f *Field) string {
+	if f.Env != "" {
+		return f.Env
+	}
+
+	r := strings.NewReplacer(b.opts.Separator, "_", "-", "_", ".", "_")
+	return strings.ToUpper(r.Replace(f.Key))
+}
+
+// envValue formats a value for a .env file. Lists are comma separated, maps are written
+// as key=value pairs, and anything containing whitespace or quotes is quoted.
+func envValue(value any) string {
+	s := ""
+	switch v := value.(type) {
+	case []any:
+		items := make([]string, len(v))
+		for i, item := range v {
+			if _, ok := item.(map[string]any); ok {
+				j, _ := json.Marshal(v)
+				return strconv.Quote(string(j))
+			}
+
+			items[i] = fmt.Sprint(item)
+		}
+
+		s = strings.Join(items, ",")
+	case map[string]any:
+		items := make([]string, 0, len(v))
+		for k, item := range v {
+			items = append(items, fmt.Sprintf("%s=%v", k, item))
+		}
+
+		sort.Strings(items)
+		s = strings.Join(items, ",")
+	default:
+		s = fmt.Sprint(v)
+	}
+
+	if strings.ContainsAny(s, " \t\n\"'#$") {
+		return strconv.Quote(s)
+	}
+
+	return s
+}
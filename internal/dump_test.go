@@ -0,0 +1,90 @@
+package internal
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// Dump.
+type DumpFormats struct {
+	Name    string            `config:"mamba,The name to use"`
+	Timeout time.Duration     `config:"30s,The timeout"`
+	Tags    []string          `config:"default=[\"a\",\"b\"];desc=The tags"`
+	Labels  map[string]string `config:"default=team=snakes;desc=The labels"`
+	Server  DumpFormatsServer `config:""`
+}
+
+type DumpFormatsServer struct {
+	Port int `config:"8080,The port to listen on"`
+}
+
+func dump(t *testing.T, format Format, args ...string) string {
+	out := &bytes.Buffer{}
+	cmd := &cobra.Command{Use: "app", RunE: func(c *cobra.Command, _ []string) error { return Dump(out, format, c) }}
+	err := Bind(DumpFormats{}, cmd, &Options{Viper: viper.New(), EnvPrefix: "APP"})
+	assertNil(t, err)
+
+	cmd.SetArgs(args)
+	err = cmd.Execute()
+	assertNil(t, err)
+
+	return out.String()
+}
+
+func TestDumpYAML(t *testing.T) {
+	out := dump(t, FormatYAML, "--server.port", "9090")
+
+	assertEqual(t, "labels:\n  team: snakes\nname: mamba\nserver:\n  port: 9090\ntags:\n  - a\n  - b\ntimeout: 30s\n", out)
+}
+
+func TestDumpJSON(t *testing.T) {
+	out := dump(t, FormatJSON, "--name", "viper")
+
+	assertEqual(t, true, strings.Contains(out, "\"name\": \"viper\""))
+	assertEqual(t, true, strings.Contains(out, "\"server\": {\n    \"port\": 8080\n  }"))
+}
+
+func TestDumpTOML(t *testing.T) {
+	out := dump(t, FormatTOML)
+
+	assertEqual(t, true, strings.Contains(out, "name = 'mamba'"))
+	assertEqual(t, true, strings.Contains(out, "[server]\nport = 8080"))
+}
+
+func TestDumpEnv(t *testing.T) {
+	out := dump(t, FormatEnv, "--name", "black mamba")
+
+	assertEqual(t, "APP_LABELS=team=snakes\nAPP_NAME=\"black mamba\"\nAPP_SERVER_PORT=8080\nAPP_TAGS=a,b\nAPP_TIMEOUT=30s\n", out)
+}
+
+func TestDumpUnsupportedFormat(t *testing.T) {
+	cmd := &cobra.Command{}
+	err := Bind(DumpFormats{}, cmd, &Options{Viper: viper.New()})
+	assertNil(t, err)
+
+	err = Dump(&bytes.Buffer{}, "xml", cmd)
+	assertError(t, err)
+}
+
+func TestConfigPrintCommand(t *testing.T) {
+	out := &bytes.Buffer{}
+	root := &cobra.Command{Use: "app"}
+	root.SetOut(out)
+	err := Bind(DumpFormats{}, root, &Options{Viper: viper.New(), Persistent: true, ConfigCommand: true})
+	assertNil(t, err)
+
+	root.SetArgs([]string{"config", "print", "--format", "env", "--name", "viper"})
+	err = root.Execute()
+	assertNil(t, err)
+
+	assertEqual(t, true, strings.Contains(out.String(), "NAME=viper\n"))
+
+	root.SetArgs([]string{"config", "print", "--format", "xml"})
+	err = root.Execute()
+	assertError(t, err)
+}
@@ -0,0 +1,41 @@
+package internal
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// enumValue wraps a flag value, rejecting anything outside of the allowed choices
+// before it is set.
+type enumValue struct {
+	pflag.Value
+	choices []string
+}
+
+func (e *enumValue) Set(s string) error {
+	items := []string{s}
+	if strings.HasSuffix(e.Type(), "Slice") {
+		items = strings.Split(s, ",")
+	}
+
+	for _, i := range items {
+		if !slices.Contains(e.choices, strings.TrimSpace(i)) {
+			return fmt.Errorf("must be one of %s", strings.Join(e.choices, ", "))
+		}
+	}
+
+	return e.Value.Set(s)
+}
+
+// restrict limits a flag to the given choices and registers them for shell completion.
+func (b *Binder) restrict(cmd *cobra.Command, fl *pflag.Flag, choices []string) error {
+	fl.Value = &enumValue{fl.Value, choices}
+
+	return cmd.RegisterFlagCompletionFunc(fl.Name, func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+		return choices, cobra.ShellCompDirectiveNoFileComp
+	})
+}
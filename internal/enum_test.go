@@ -0,0 +1,56 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// Enum.
+type BindEnumRestrictsValues struct {
+	Level  string   `config:"default=info;desc=The log level;oneof=debug|info|warn|error"`
+	Format string   `config:"text,The log format,false,,,enum=text|json"`
+	Sinks  []string `config:"default=[\"stdout\"];desc=The log sinks;oneof=stdout|stderr|file"`
+}
+
+func TestBindEnumRestrictsValues(t *testing.T) {
+	cmd := &cobra.Command{}
+	err := Bind(BindEnumRestrictsValues{}, cmd, &Options{Viper: viper.New()})
+	assertNil(t, err)
+
+	err = cmd.ParseFlags([]string{"--level", "warn", "--format", "json", "--sinks", "stdout,file"})
+	assertNil(t, err)
+
+	s, err := cmd.Flags().GetString("level")
+	assertEqual(t, "warn", s)
+	assertNil(t, err)
+
+	err = cmd.ParseFlags([]string{"--level", "trace"})
+	assertError(t, err)
+
+	err = cmd.ParseFlags([]string{"--sinks", "stdout,syslog"})
+	assertError(t, err)
+}
+
+func TestBindEnumListsChoices(t *testing.T) {
+	cmd := &cobra.Command{}
+	err := Bind(BindEnumRestrictsValues{}, cmd, &Options{Viper: viper.New()})
+	assertNil(t, err)
+
+	assertEqual(t, "The log level (one of: debug, info, warn, error)", cmd.Flags().Lookup("level").Usage)
+	assertEqual(t, "The log format (one of: text, json)", cmd.Flags().Lookup("format").Usage)
+}
+
+func TestBindEnumRegistersCompletion(t *testing.T) {
+	cmd := &cobra.Command{}
+	err := Bind(BindEnumRestrictsValues{}, cmd, &Options{Viper: viper.New()})
+	assertNil(t, err)
+
+	fn, ok := cmd.GetFlagCompletionFunc("level")
+	assertEqual(t, true, ok)
+
+	choices, directive := fn(cmd, nil, "")
+	assertSliceEqual(t, []string{"debug", "info", "warn", "error"}, choices)
+	assertEqual(t, cobra.ShellCompDirectiveNoFileComp, directive)
+}
@@ -1,14 +1,20 @@
 package internal
 
 import (
+	"errors"
 	"fmt"
 	"reflect"
+	"strings"
 )
 
 var InvalidTypeError *invalidTypeError = &invalidTypeError{}
 var BindError *bindError = &bindError{}
 var ParseError *parseError = &parseError{}
 var TagParseError *tagParseError = &tagParseError{}
+var DecodeError *decodeError = &decodeError{}
+var ValidationError *validationError = &validationError{}
+var ConfigError *configError = &configError{}
+var AggregateError *aggregateError = &aggregateError{}
 
 type genericError struct {
 	Kind          reflect.Kind
@@ -117,3 +123,132 @@ func (e *tagParseError) Error() string {
 func (e *tagParseError) Is(target error) bool {
 	return reflect.TypeOf(target) == reflect.TypeOf(&tagParseError{})
 }
+
+type decodeError struct {
+	*genericError
+	Value any
+}
+
+func NewDecodeError(value any, kind reflect.Kind, fieldName string, err ...error) *decodeError {
+	var e error
+	if len(err) > 0 {
+		e = err[0]
+	}
+
+	return &decodeError{&genericError{kind, fieldName, e}, value}
+}
+
+func (e *decodeError) Error() string {
+	if e.InternalError != nil {
+		return fmt.Sprintf("error decoding value \"%v\" to type \"%s\" for \"%s\": %v", e.Value, e.Kind.String(), e.FieldName, e.InternalError)
+	}
+
+	return fmt.Sprintf("error decoding value \"%v\" to type \"%s\" for \"%s\"", e.Value, e.Kind.String(), e.FieldName)
+}
+
+func (e *decodeError) Is(target error) bool {
+	return reflect.TypeOf(target) == reflect.TypeOf(&decodeError{})
+}
+
+type configError struct {
+	*genericError
+	Path string
+}
+
+func NewConfigError(path string, fieldName string, err ...error) *configError {
+	var e error
+	if len(err) > 0 {
+		e = err[0]
+	}
+
+	return &configError{&genericError{reflect.String, fieldName, e}, path}
+}
+
+func (e *configError) Error() string {
+	if e.InternalError != nil {
+		return fmt.Sprintf("error reading config file \"%s\" for \"%s\": %v", e.Path, e.FieldName, e.InternalError)
+	}
+
+	return fmt.Sprintf("error reading config file \"%s\" for \"%s\"", e.Path, e.FieldName)
+}
+
+func (e *configError) Is(target error) bool {
+	return reflect.TypeOf(target) == reflect.TypeOf(&configError{})
+}
+
+type validationError struct {
+	Violations []Violation
+}
+
+func NewValidationError(violations []Violation) *validationError {
+	return &validationError{violations}
+}
+
+func (e *validationError) Error() string {
+	s := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		s[i] = v.String()
+	}
+
+	return fmt.Sprintf("invalid configuration: %s", strings.Join(s, "; "))
+}
+
+func (e *validationError) Is(target error) bool {
+	return reflect.TypeOf(target) == reflect.TypeOf(&validationError{})
+}
+
+// aggregateError holds every error found while binding a struct, rather than just the
+// first. Each error can still be matched with errors.Is and errors.As.
+type aggregateError struct {
+	Errors []error
+}
+
+func NewAggregateError(errs []error) *aggregateError {
+	return &aggregateError{errs}
+}
+
+func (e *aggregateError) Error() string {
+	s := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		s[i] = err.Error()
+	}
+
+	return fmt.Sprintf("%d errors binding config: %s", len(e.Errors), strings.Join(s, "; "))
+}
+
+func (e *aggregateError) Is(target error) bool {
+	return reflect.TypeOf(target) == reflect.TypeOf(&aggregateError{})
+}
+
+func (e *aggregateError) Unwrap() []error {
+	return e.Errors
+}
+
+// Fields returns the path of the field each error is for.
+func (e *aggregateError) Fields() []string {
+	fields := []string{}
+	for _, err := range e.Errors {
+		fields = append(fields, ErrorFields(err)...)
+	}
+
+	return fields
+}
+
+func (e *genericError) field() string {
+	return e.FieldName
+}
+
+// ErrorFields returns the paths of the fields an error returned by Bind or BindAll is for.
+func ErrorFields(err error) []string {
+	var agg *aggregateError
+	if errors.As(err, &agg) {
+		return agg.Fields()
+	}
+
+	var f interface{ field() string }
+	if errors.As(err, &f) {
+		return []string{f.field()}
+	}
+
+	return nil
+}
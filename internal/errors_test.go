@@ -0,0 +1,68 @@
+package internal
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// Aggregate errors.
+type BindAllErrors struct {
+	Port    int              `config:"eighty,The port"`
+	Rate    float64          `config:"fast,The rate"`
+	Name    string           `config:"mamba,The name"`
+	Level   string           `config:"default=info;oneof="`
+	Channel chan int         `config:",A channel"`
+	Pointer *int             `config:"1,A pointer"`
+	Server  BindAllErrorsSub `config:""`
+}
+
+type BindAllErrorsSub struct {
+	Enabled bool `config:"maybe,Whether it is enabled"`
+}
+
+func TestBindAllCollectsErrors(t *testing.T) {
+	cmd := &cobra.Command{}
+	err := BindAll(BindAllErrors{}, cmd, &Options{Viper: viper.New()})
+	assertErrorIs(t, err, &aggregateError{})
+	for _, target := range []error{ParseError, TagParseError, InvalidTypeError} {
+		if !errors.Is(err, target) {
+			t.Errorf("expected %v to match %T", err, target)
+		}
+	}
+
+	var agg *aggregateError
+	if !errors.As(err, &agg) {
+		t.Fatalf("expected an aggregate error but got %v", err)
+	}
+
+	assertEqual(t, 6, len(agg.Errors))
+	assertSliceEqual(t, []string{"port", "rate", "level", "channel", "pointer", "server.enabled"}, ErrorFields(err))
+
+	var perr *parseError
+	if !errors.As(err, &perr) {
+		t.Fatalf("expected a parse error but got %v", err)
+	}
+
+	assertEqual(t, "port", perr.FieldName)
+	assertEqual(t, "mamba", cmd.Flags().Lookup("name").DefValue)
+}
+
+func TestBindStopsAtFirstError(t *testing.T) {
+	err := Bind(BindAllErrors{}, &cobra.Command{}, &Options{Viper: viper.New()})
+	assertErrorIs(t, err, &parseError{})
+	assertSliceEqual(t, []string{"port"}, ErrorFields(err))
+}
+
+func TestAllErrorsReturnedBySamples(t *testing.T) {
+	opts := &Options{AllErrors: true}
+
+	_, err := GenerateSample(BindAllErrors{}, FormatYAML, opts)
+	assertErrorIs(t, err, &aggregateError{})
+	assertSliceEqual(t, []string{"port", "rate", "level", "channel", "pointer", "server.enabled"}, ErrorFields(err))
+
+	_, err = JSONSchema(BindAllErrors{}, opts)
+	assertErrorIs(t, err, &aggregateError{})
+}
@@ -0,0 +1,104 @@
+package internal
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+const explainFlag = "config-explain"
+
+// Sources a value can come from, in order of precedence.
+const (
+	SourceFlag    = "flag"
+	SourceEnv     = "env"
+	SourceConfig  = "config"
+	SourceDefault = "default"
+)
+
+// Explanation describes the final value of a bound key and where it came from.
+type Explanation struct {
+	Key   string
+	Value any
+
+	// Source is one of SourceFlag, SourceEnv, SourceConfig or SourceDefault.
+	Source string
+
+	// Origin is the flag, env var or config file that supplied the value. It is empty
+	// for defaults.
+	Origin string
+}
+
+func (e Explanation) String() string {
+	if e.Origin == "" {
+		return fmt.Sprintf("%s = %v (%s)", e.Key, e.Value, e.Source)
+	}
+
+	return fmt.Sprintf("%s = %v (%s %s)", e.Key, e.Value, e.Source, e.Origin)
+}
+
+// Explain returns the final value, and its source, of every key bound to a command or
+// its parents.
+func Explain(cmd *cobra.Command) []Explanation {
+	layers := LayerReport(cmd)
+
+	explanations := []Explanation{}
+	for _, b := range binders(cmd) {
+		for _, f := range b.fields {
+			explanations = append(explanations, b.explain(f, layers))
+		}
+	}
+
+	return explanations
+}
+
+func (b *Binder) explain(f *Field, layers map[string]string) Explanation {
+	e := Explanation{Key: f.Key, Source: SourceDefault}
+
+	v, err := b.resolve(f)
+	if err != nil {
+		e.Value = b.viper().Get(f.Key)
+	} else {
+		e.Value = v.Interface()
+	}
+
+	if f.Tag.Secret {
+		e.Value = redacted
+	}
+
+	switch {
+	case f.Flag != nil && f.Flag.Changed:
+		e.Source, e.Origin = SourceFlag, fmt.Sprintf("--%s", f.Flag.Name)
+	case f.Env != "" && envSet(f.Env):
+		e.Source, e.Origin = SourceEnv, f.Env
+	case f.Env != "" && f.Tag.Secret && envSet(secretFileEnv(f.Env)):
+		e.Source, e.Origin = SourceEnv, secretFileEnv(f.Env)
+	case b.viper().InConfig(f.Key):
+		e.Source, e.Origin = SourceConfig, layers[f.Key]
+		if e.Origin == "" {
+			e.Origin = b.viper().ConfigFileUsed()
+		}
+	}
+
+	return e
+}
+
+// envSet reports whether an env var is set to a value that viper will use. Like viper,
+// empty values are treated as unset.
+func envSet(name string) bool {
+	return os.Getenv(name) != ""
+}
+
+// WriteExplain writes the explanation of every key bound to a command as a table.
+func WriteExplain(w io.Writer, cmd *cobra.Command) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "KEY\tVALUE\tSOURCE\tORIGIN")
+	for _, e := range Explain(cmd) {
+		fmt.Fprintf(tw, "%s\t%v\t%s\t%s\n", e.Key, e.Value, e.Source, e.Origin)
+	}
+
+	return tw.Flush()
+}
@@ -0,0 +1,80 @@
+package internal
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// Explain.
+type ExplainSources struct {
+	Config string              `config:"default=;desc=The config file to read;configfile"`
+	Server ExplainSourcesInner `config:""`
+}
+
+type ExplainSourcesInner struct {
+	Host    string `config:"localhost,The host to listen on"`
+	Port    int    `config:"8080,The port to listen on"`
+	Name    string `config:"mamba,The name to use"`
+	Timeout int    `config:"30,The timeout in seconds"`
+}
+
+func TestExplainSources(t *testing.T) {
+	path := writeConfig(t, t.TempDir(), "app.yaml", "server:\n  name: file\n  port: 1000\n")
+	t.Setenv("APP_SERVER_HOST", "env.local")
+
+	// Empty env vars are ignored by viper, so don't supply a value.
+	t.Setenv("APP_SERVER_NAME", "")
+	t.Setenv("APP_SERVER_TIMEOUT", "")
+
+	var explanations []Explanation
+	opts := &Options{Viper: viper.New(), EnvPrefix: "APP"}
+	cmd := &cobra.Command{Use: "app", Run: func(c *cobra.Command, _ []string) { explanations = Explain(c) }}
+	err := Bind(ExplainSources{}, cmd, opts)
+	assertNil(t, err)
+
+	cmd.SetArgs([]string{"--config", path, "--server.port", "2000"})
+	err = cmd.Execute()
+	assertNil(t, err)
+
+	byKey := map[string]Explanation{}
+	for _, e := range explanations {
+		byKey[e.Key] = e
+	}
+
+	assertEqual(t, SourceEnv, byKey["server.host"].Source)
+	assertEqual(t, "APP_SERVER_HOST", byKey["server.host"].Origin)
+	assertEqual(t, "env.local", byKey["server.host"].Value.(string))
+
+	assertEqual(t, SourceFlag, byKey["server.port"].Source)
+	assertEqual(t, "--server.port", byKey["server.port"].Origin)
+	assertEqual(t, 2000, byKey["server.port"].Value.(int))
+
+	assertEqual(t, SourceConfig, byKey["server.name"].Source)
+	assertEqual(t, path, byKey["server.name"].Origin)
+
+	assertEqual(t, SourceDefault, byKey["server.timeout"].Source)
+	assertEqual(t, 30, byKey["server.timeout"].Value.(int))
+}
+
+func TestExplainFlagPrints(t *testing.T) {
+	out := &bytes.Buffer{}
+	opts := &Options{Viper: viper.New(), ExplainFlag: true}
+	root := &cobra.Command{Use: "app"}
+	sub := &cobra.Command{Use: "serve", Run: func(*cobra.Command, []string) {}}
+	root.AddCommand(sub)
+	root.SetErr(out)
+
+	err := Bind(ExplainSources{}, root, opts)
+	assertNil(t, err)
+
+	root.SetArgs([]string{"serve", "--config-explain"})
+	err = root.Execute()
+	assertNil(t, err)
+
+	assertEqual(t, true, strings.Contains(out.String(), "server.port"))
+	assertEqual(t, true, strings.Contains(out.String(), "default"))
+}
@@ -0,0 +1,64 @@
+package internal
+
+import (
+	"reflect"
+	"sync"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// Field describes a single field bound by a Binder.
+type Field struct {
+	Key  string
+	Env  string
+	Type reflect.Type
+	Tag  *Tag
+	Flag *pflag.Flag
+
+	// Description is the description from the tag, without the choices and env var that
+	// are added to it for the help text.
+	Description string
+}
+
+// bound records the binders used for each command so that the fields they bound can
+// be looked up later, e.g. from within a pre-run hook of a sub command.
+var bindersMu sync.RWMutex
+var bound = map[*cobra.Command][]*Binder{}
+
+func register(cmd *cobra.Command, b *Binder) {
+	bindersMu.Lock()
+	defer bindersMu.Unlock()
+
+	bound[cmd] = append(bound[cmd], b)
+}
+
+// binders returns the binders for a command and each of its parents, closest first.
+func binders(cmd *cobra.Command) []*Binder {
+	bindersMu.RLock()
+	defer bindersMu.RUnlock()
+
+	all := []*Binder{}
+	for c := cmd; c != nil; c = c.Parent() {
+		all = append(all, bound[c]...)
+	}
+
+	return all
+}
+
+// registered returns a binder that bound a struct of type t with the given options, or
+// nil if there is none.
+func registered(t reflect.Type, opts *Options) *Binder {
+	bindersMu.RLock()
+	defer bindersMu.RUnlock()
+
+	for _, all := range bound {
+		for _, b := range all {
+			if b.typ == t && b.opts == opts {
+				return b
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,69 @@
+package internal
+
+import "github.com/spf13/cobra"
+
+// preRun is installed as a persistent pre-run hook on commands that need config files
+// or secrets reading, or values explaining, before they run.
+func (b *Binder) preRun(cmd *cobra.Command, _ []string) error {
+	err := b.read(cmd)
+	if err != nil {
+		return err
+	}
+
+	if fl := cmd.Flag(explainFlag); b.opts.ExplainFlag && fl != nil && fl.Value.String() == "true" {
+		return WriteExplain(cmd.ErrOrStderr(), cmd)
+	}
+
+	return nil
+}
+
+// addPreRun installs fn as a persistent pre-run hook on cmd. Any hook already present
+// is kept, and runs after fn.
+func addPreRun(cmd *cobra.Command, fn func(cmd *cobra.Command, args []string) error) {
+	preRun, preRunE := cmd.PersistentPreRun, cmd.PersistentPreRunE
+
+	cmd.PersistentPreRun = nil
+	cmd.PersistentPreRunE = func(c *cobra.Command, args []string) error {
+		err := fn(c, args)
+		if err != nil {
+			return err
+		}
+
+		if preRunE != nil {
+			return preRunE(c, args)
+		}
+
+		if preRun != nil {
+			preRun(c, args)
+		}
+
+		return nil
+	}
+}
+
+// ReadConfig reads the config files and secret files for the fields bound to cmd and its
+// parents, as the pre-run hook installed by Bind does. Cobra only runs the closest
+// persistent pre-run hook, so a sub command with its own hook must call this from it.
+func ReadConfig(cmd *cobra.Command) error {
+	all := binders(cmd)
+	for i := len(all) - 1; i >= 0; i-- {
+		err := all[i].read(cmd)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// read reads the config files and secret files for the fields of a binder.
+func (b *Binder) read(cmd *cobra.Command) error {
+	if b.configKey != "" || len(b.opts.ConfigLayers) > 0 {
+		err := b.readConfig(cmd)
+		if err != nil {
+			return err
+		}
+	}
+
+	return b.readSecretFiles()
+}
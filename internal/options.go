@@ -1,5 +1,11 @@
 package internal
 
+import (
+	"reflect"
+
+	"github.com/spf13/viper"
+)
+
 // Options allows for configuring the mamba binder.
 type Options struct {
 	// Persistent (Default `false`) will cause all flags to be bound as persistent.
@@ -28,4 +34,83 @@ type Options struct {
 	// Use with caution, properties with the same name in two embdedded structs will fail
 	// to bind with this option set to false
 	PrefixEmbedded bool
+
+	// EnvPrefix (Default `""`) enables environment variable binding for every field. The
+	// variable name is the prefix followed by the key in upper case, with separators
+	// replaced by underscores. For example a prefix of `MYAPP` gives `MYAPP_SERVER_PORT`
+	// for `server.port`. Fields can also name their own variable via the env tag part.
+	EnvPrefix string
+
+	// AllErrors (Default `false`) keeps binding the remaining fields when one fails, so that
+	// every error is returned at once. The errors are joined in an error that matches
+	// AggregateError, and each can still be checked with errors.Is and errors.As. BindAll
+	// always sets this.
+	AllErrors bool
+
+	// Strict (Default `false`) returns an InvalidTypeError for slice fields with an element
+	// type that can't be bound, rather than skipping them.
+	Strict bool
+
+	// OnSkip (Default `nil`) is called with the key of every exported field that is not
+	// bound, and the reason why, e.g. because it has no config tag or is a slice of an
+	// unsupported type.
+	OnSkip func(key string, reason string)
+
+	// UseStructValues (Default `false`) uses the values already set on the struct passed to
+	// Bind as the defaults of its flags. Any non-zero field overrides the default from its
+	// tag, allowing defaults to be computed, e.g. by a `DefaultConfig()` constructor.
+	UseStructValues bool
+
+	// TimeLayout (Default `time.RFC3339`) is the layout used to parse time.Time fields.
+	// It can be overridden per field with the `layout` key of the named tag form.
+	TimeLayout string
+
+	// ConfigFlag (Default `""`) names the key of a string field holding the path of a config
+	// file, e.g. `configfile`. The field can also be marked with the `configfile` key of the
+	// named tag form. When set, a persistent pre-run hook is installed on the command that
+	// reads the file, or searches ConfigPaths for one when the field is empty.
+	ConfigFlag string
+
+	// ConfigName (Defaults to the name of the root command) is the name, without extension,
+	// of the config file to search for when no path is given.
+	ConfigName string
+
+	// ConfigPaths (Defaults to `$XDG_CONFIG_HOME/<name>`, `/etc/<name>` and the working
+	// directory) are the directories searched for a config file when no path is given.
+	ConfigPaths []string
+
+	// ConfigLayers are config files merged in order on top of one another, e.g. a base file,
+	// then a profile, then local overrides. A `{profile}` placeholder is replaced with the
+	// profile selected via ProfileFlag, and layers using it are skipped when no profile is
+	// selected. Other layers are optional and skipped when they do not exist. A file named
+	// by the ConfigFlag field is merged last.
+	ConfigLayers []string
+
+	// ProfileFlag (Default `profile`) is the name of the persistent flag registered to select
+	// a profile when any of the ConfigLayers contain a `{profile}` placeholder.
+	ProfileFlag string
+
+	// ExplainFlag (Default `false`) registers a persistent `--config-explain` flag which
+	// prints the final value of every bound key, and where it came from, before the
+	// command runs.
+	ExplainFlag bool
+
+	// ConfigCommand (Default `false`) adds a `config` sub command to the command, with a
+	// `print` sub command that writes the effective configuration as YAML, JSON, TOML or
+	// in .env format, and an `init` sub command that writes a sample config file.
+	ConfigCommand bool
+
+	// OnReloadError (Default `nil`) is called by Watch with the error when a changed config
+	// file fails to load or validate. The previous value is kept.
+	OnReloadError func(err error)
+
+	// Converters allows types that mamba does not support natively to be bound, keyed by
+	// the type of the field. These take precedence over converters registered globally
+	// with RegisterType, and over the built in handling of every type.
+	Converters map[reflect.Type]Converter
+
+	// Viper (Defaults to the global instance) is the viper instance that flags will be bound
+	// to and values loaded from. Supplying a dedicated instance keeps the config for separate
+	// command trees isolated from one another.
+	Viper *viper.Viper
 }
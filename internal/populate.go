@@ -0,0 +1,351 @@
+package internal
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/spf13/cast"
+)
+
+// Load populates the struct pointed to by obj with the values resolved by viper, and
+// then validates it against the rules in the config tags.
+func Load(obj any, options ...*Options) error {
+	b := newBinder(options...)
+
+	err := b.Populate(obj)
+	if err != nil {
+		return err
+	}
+
+	return b.Validate(obj)
+}
+
+// Populate walks the same fields as Bind, setting each one from the value viper has
+// resolved for its key. Precedence is therefore the usual viper order of flag, env,
+// config file and then default.
+func (b *Binder) Populate(obj any) error {
+	v := reflect.ValueOf(obj)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return NewInvalidTypeError(v.Kind(), "obj", fmt.Errorf("expected a non-nil pointer to a struct"))
+	}
+
+	return b.walkFields("", v.Elem(), b.populateField)
+}
+
+func (b *Binder) populateField(n string, t *Tag, field reflect.StructField, v reflect.Value) error {
+	k := field.Type.Kind()
+	raw := b.viper().Get(n)
+	if raw == nil {
+		return nil
+	}
+
+	if c := b.converter(field.Type); c != nil {
+		d, err := c.Decode(raw)
+		if err != nil {
+			return b.decodeError(raw, t, k, n, err)
+		}
+
+		dv := reflect.ValueOf(d)
+		if !dv.IsValid() || !dv.Type().ConvertibleTo(field.Type) {
+			return NewDecodeError(raw, k, n, fmt.Errorf("converter returned %T, expected %s", d, field.Type))
+		}
+
+		v.Set(dv.Convert(field.Type))
+		return nil
+	}
+
+	if b.isStructSlice(field.Type) {
+		err := b.populateStructs(n, t, raw, v)
+		if err != nil {
+			return b.decodeError(raw, t, k, n, err)
+		}
+
+		return nil
+	}
+
+	if s, ok := raw.(string); ok && isBytes(field.Type) {
+		d, err := decodeBytes(s, t.Encoding)
+		if err != nil {
+			return b.decodeError(raw, t, k, n, err)
+		}
+
+		v.Set(reflect.ValueOf(d).Convert(field.Type))
+		return nil
+	}
+
+	if s, ok := raw.(string); ok && field.Type == timeType {
+		if d, err := time.Parse(b.layout(t), s); err == nil {
+			v.Set(reflect.ValueOf(d))
+			return nil
+		}
+	}
+
+	err := decode(raw, v)
+	if err != nil {
+		return b.decodeError(raw, t, k, n, err)
+	}
+
+	return nil
+}
+
+// decodeError returns a DecodeError for a value, leaving out the value of secrets as well
+// as the underlying error, which often repeats it.
+func (b *Binder) decodeError(raw any, t *Tag, k reflect.Kind, n string, err error) error {
+	if t.Secret {
+		return NewDecodeError(redacted, k, n)
+	}
+
+	return NewDecodeError(raw, k, n, err)
+}
+
+// resolve returns the final value of a bound field as its own type.
+func (b *Binder) resolve(f *Field) (reflect.Value, error) {
+	v := reflect.New(f.Type).Elem()
+	err := b.populateField(f.Key, f.Tag, reflect.StructField{Type: f.Type}, v)
+
+	return v, err
+}
+
+// decode converts a value resolved by viper into the type of v and sets it.
+func decode(raw any, v reflect.Value) error {
+	switch v.Type() {
+	case durationType:
+		d, err := cast.ToDurationE(raw)
+		if err != nil {
+			return err
+		}
+
+		v.SetInt(int64(d))
+		return nil
+	case timeType:
+		d, err := cast.ToTimeE(raw)
+		if err != nil {
+			return err
+		}
+
+		v.Set(reflect.ValueOf(d))
+		return nil
+	}
+
+	if isCustom(v.Type()) {
+		s, err := cast.ToStringE(raw)
+		if err != nil {
+			return err
+		}
+
+		c, target := newCustom(v.Type())
+		err = c.Set(s)
+		if err != nil {
+			return err
+		}
+
+		v.Set(target)
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := cast.ToInt64E(raw)
+		if err != nil {
+			return err
+		}
+
+		if v.OverflowInt(i) {
+			return fmt.Errorf("value %d overflows %s", i, v.Type())
+		}
+
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		i, err := cast.ToUint64E(raw)
+		if err != nil {
+			return err
+		}
+
+		if v.OverflowUint(i) {
+			return fmt.Errorf("value %d overflows %s", i, v.Type())
+		}
+
+		v.SetUint(i)
+	case reflect.Float32, reflect.Float64:
+		f, err := cast.ToFloat64E(raw)
+		if err != nil {
+			return err
+		}
+
+		if v.OverflowFloat(f) {
+			return fmt.Errorf("value %v overflows %s", f, v.Type())
+		}
+
+		v.SetFloat(f)
+	case reflect.Bool:
+		b, err := cast.ToBoolE(raw)
+		if err != nil {
+			return err
+		}
+
+		v.SetBool(b)
+	case reflect.String:
+		s, err := cast.ToStringE(raw)
+		if err != nil {
+			return err
+		}
+
+		v.SetString(s)
+	case reflect.Slice:
+		items, err := toSlice(raw)
+		if err != nil {
+			return err
+		}
+
+		s := reflect.MakeSlice(v.Type(), len(items), len(items))
+		for i, item := range items {
+			err := decode(item, s.Index(i))
+			if err != nil {
+				return err
+			}
+		}
+
+		v.Set(s)
+	case reflect.Array:
+		items, err := toSlice(raw)
+		if err != nil {
+			return err
+		}
+
+		if len(items) != v.Len() {
+			return fmt.Errorf("expected %d values for %s but got %d", v.Len(), v.Type(), len(items))
+		}
+
+		for i, item := range items {
+			err := decode(item, v.Index(i))
+			if err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		items, err := toMap(raw)
+		if err != nil {
+			return err
+		}
+
+		m := reflect.MakeMapWithSize(v.Type(), len(items))
+		for key, item := range items {
+			k := reflect.New(v.Type().Key()).Elem()
+			err := decode(key, k)
+			if err != nil {
+				return err
+			}
+
+			e := reflect.New(v.Type().Elem()).Elem()
+			err = decode(item, e)
+			if err != nil {
+				return err
+			}
+
+			m.SetMapIndex(k, e)
+		}
+
+		v.Set(m)
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+
+	return nil
+}
+
+// toSlice normalises the different shapes a list can take in viper. Values read from
+// config files are already slices, whereas flags that viper does not natively understand
+// are returned as their string form, e.g. `[1,2,3]`.
+func toSlice(raw any) ([]any, error) {
+	if s, ok := raw.(string); ok {
+		s = strings.TrimSpace(s)
+
+		var items []any
+		d := json.NewDecoder(strings.NewReader(s))
+		d.UseNumber()
+		if err := d.Decode(&items); err == nil && !d.More() {
+			return items, nil
+		}
+
+		s = strings.TrimSuffix(strings.TrimPrefix(s, "["), "]")
+		if s == "" {
+			return []any{}, nil
+		}
+
+		record, err := csv.NewReader(strings.NewReader(s)).Read()
+		if err != nil {
+			return nil, err
+		}
+
+		items = make([]any, len(record))
+		for i, r := range record {
+			items[i] = r
+		}
+
+		return items, nil
+	}
+
+	rv := reflect.ValueOf(raw)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return []any{raw}, nil
+	}
+
+	items := make([]any, rv.Len())
+	for i := range items {
+		items[i] = rv.Index(i).Interface()
+	}
+
+	return items, nil
+}
+
+// toMap normalises the different shapes a map can take in viper. Values read from
+// config files are already maps, whereas flags and env vars give a string of either
+// json or comma separated pairs, e.g. `[a=1,b=2]`.
+func toMap(raw any) (map[string]any, error) {
+	if s, ok := raw.(string); ok {
+		s = strings.TrimSpace(s)
+
+		items := map[string]any{}
+		if err := json.Unmarshal([]byte(s), &items); err == nil {
+			return items, nil
+		}
+
+		s = strings.TrimSuffix(strings.TrimPrefix(s, "["), "]")
+		if s == "" {
+			return items, nil
+		}
+
+		record, err := csv.NewReader(strings.NewReader(s)).Read()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, r := range record {
+			key, value, found := strings.Cut(r, "=")
+			if !found {
+				return nil, fmt.Errorf("%s must be formatted as key=value", r)
+			}
+
+			items[strings.TrimSpace(key)] = strings.TrimSpace(value)
+		}
+
+		return items, nil
+	}
+
+	rv := reflect.ValueOf(raw)
+	if rv.Kind() != reflect.Map {
+		return nil, fmt.Errorf("unable to convert %T to a map", raw)
+	}
+
+	items := make(map[string]any, rv.Len())
+	iter := rv.MapRange()
+	for iter.Next() {
+		items[fmt.Sprint(iter.Key().Interface())] = iter.Value().Interface()
+	}
+
+	return items, nil
+}
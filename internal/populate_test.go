@@ -0,0 +1,257 @@
+package internal
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// Populate.
+type PopulateSetsDefaults struct {
+	PopulateInt    int                       `config:"12,The int to populate"`
+	PopulateString string                    `config:"populated,The string to populate"`
+	PopulateSlice  []string                  `config:"\"[\"\"a\"\",\"\"b\"\"]\",The slice to populate"`
+	PopulateInner  PopulateSetsDefaultsInner `config:""`
+}
+
+type PopulateSetsDefaultsInner struct {
+	Float float64 `config:"1.5,The float to populate"`
+}
+
+func TestPopulateSetsDefaults(t *testing.T) {
+	cmd := &cobra.Command{}
+	err := Bind(PopulateSetsDefaults{}, cmd)
+	assertNil(t, err)
+
+	cfg := &PopulateSetsDefaults{}
+	err = Load(cfg)
+	assertNil(t, err)
+
+	assertEqual(t, 12, cfg.PopulateInt)
+	assertEqual(t, "populated", cfg.PopulateString)
+	assertSliceEqual(t, []string{"a", "b"}, cfg.PopulateSlice)
+	assertEqual(t, 1.5, cfg.PopulateInner.Float)
+}
+
+type PopulateUsesFlags struct {
+	PopulateFlagInt   int       `config:"12,The int to populate"`
+	PopulateFlagSlice []float64 `config:"\"[1,2]\",The slice to populate"`
+}
+
+func TestPopulateUsesFlags(t *testing.T) {
+	cmd := &cobra.Command{}
+	err := Bind(PopulateUsesFlags{}, cmd)
+	assertNil(t, err)
+
+	err = cmd.ParseFlags([]string{"--populateflagint", "42", "--populateflagslice", "3.5,4.5"})
+	assertNil(t, err)
+
+	cfg := &PopulateUsesFlags{}
+	err = Load(cfg)
+	assertNil(t, err)
+
+	assertEqual(t, 42, cfg.PopulateFlagInt)
+	assertSliceEqual(t, []float64{3.5, 4.5}, cfg.PopulateFlagSlice)
+}
+
+type PopulateUsesOptions struct {
+	*PopulateUsesOptionsEmbedded `config:""`
+}
+
+type PopulateUsesOptionsEmbedded struct {
+	Inner PopulateUsesOptionsInner `config:""`
+}
+
+type PopulateUsesOptionsInner struct {
+	Value string `config:"separated,The string to populate"`
+}
+
+func TestPopulateUsesOptions(t *testing.T) {
+	opts := &Options{Separator: "-"}
+	cmd := &cobra.Command{}
+	err := Bind(PopulateUsesOptions{}, cmd, opts)
+	assertNil(t, err)
+
+	err = cmd.ParseFlags([]string{"--populateusesoptionsembedded-inner-value", "from flag"})
+	assertNil(t, err)
+
+	cfg := &PopulateUsesOptions{}
+	err = Load(cfg, opts)
+	assertNil(t, err)
+
+	assertEqual(t, "from flag", cfg.Inner.Value)
+}
+
+func TestPopulateRequiresPointer(t *testing.T) {
+	err := Load(PopulateSetsDefaults{})
+	assertErrorIs(t, err, &invalidTypeError{})
+}
+
+type PopulateUsesViperInstance struct {
+	Value string `config:"default,The string to populate"`
+}
+
+func TestPopulateUsesViperInstance(t *testing.T) {
+	opts := &Options{Viper: viper.New()}
+	opts.Viper.Set("value", "isolated")
+
+	cfg := &PopulateUsesViperInstance{}
+	err := Load(cfg, opts)
+	assertNil(t, err)
+
+	assertEqual(t, "isolated", cfg.Value)
+}
+
+type PopulateMaps struct {
+	Labels  map[string]string `config:"default=team=snakes;desc=The labels"`
+	Weights map[string]int64  `config:"default=a=1,b=2;desc=The weights"`
+}
+
+func TestPopulateMaps(t *testing.T) {
+	opts := &Options{Viper: viper.New()}
+	cmd := &cobra.Command{}
+	err := Bind(PopulateMaps{}, cmd, opts)
+	assertNil(t, err)
+
+	cfg := &PopulateMaps{}
+	err = Load(cfg, opts)
+	assertNil(t, err)
+
+	assertEqual(t, "snakes", cfg.Labels["team"])
+	assertEqual(t, int64(2), cfg.Weights["b"])
+
+	opts.Viper.SetConfigType("yaml")
+	err = opts.Viper.ReadConfig(strings.NewReader("labels:\n  team: vipers\n  region: eu\n"))
+	assertNil(t, err)
+
+	err = cmd.ParseFlags([]string{"--weights", "c=3"})
+	assertNil(t, err)
+
+	cfg = &PopulateMaps{}
+	err = Load(cfg, opts)
+	assertNil(t, err)
+
+	assertEqual(t, "vipers", cfg.Labels["team"])
+	assertEqual(t, "eu", cfg.Labels["region"])
+	assertEqual(t, 1, len(cfg.Weights))
+	assertEqual(t, int64(3), cfg.Weights["c"])
+}
+
+type PopulateUnsigned struct {
+	Workers uint   `config:"4,The workers"`
+	Size    uint64 `config:"1024,The size"`
+	Ports   []uint `config:"\"[80,443]\",The ports"`
+	Small   uint8  `config:"8,The small"`
+}
+
+func TestPopulateUnsigned(t *testing.T) {
+	opts := &Options{Viper: viper.New()}
+	cmd := &cobra.Command{}
+	err := Bind(PopulateUnsigned{}, cmd, opts)
+	assertNil(t, err)
+
+	err = cmd.ParseFlags([]string{"--workers", "16", "--ports", "8080,8443"})
+	assertNil(t, err)
+
+	cfg := &PopulateUnsigned{}
+	err = Load(cfg, opts)
+	assertNil(t, err)
+
+	assertEqual(t, uint(16), cfg.Workers)
+	assertEqual(t, uint64(1024), cfg.Size)
+	assertSliceEqual(t, []uint{8080, 8443}, cfg.Ports)
+	assertEqual(t, uint8(8), cfg.Small)
+
+	opts.Viper.Set("small", 300)
+	err = Load(cfg, opts)
+	assertErrorIs(t, err, &decodeError{})
+}
+
+type PopulateTimes struct {
+	Timeout  time.Duration   `config:"30s,The timeout"`
+	Backoffs []time.Duration `config:"\"[\"\"1s\"\"]\",The backoffs"`
+	Since    time.Time       `config:"default=2024-01-02;desc=The start date;layout=2006-01-02"`
+}
+
+func TestPopulateTimes(t *testing.T) {
+	opts := &Options{Viper: viper.New()}
+	cmd := &cobra.Command{}
+	err := Bind(PopulateTimes{}, cmd, opts)
+	assertNil(t, err)
+
+	cfg := &PopulateTimes{}
+	err = Load(cfg, opts)
+	assertNil(t, err)
+
+	assertEqual(t, 30*time.Second, cfg.Timeout)
+	assertSliceEqual(t, []time.Duration{time.Second}, cfg.Backoffs)
+	assertEqual(t, time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), cfg.Since)
+
+	err = cmd.ParseFlags([]string{"--timeout", "1m30s", "--backoffs", "1s,5s", "--since", "2025-03-04"})
+	assertNil(t, err)
+
+	err = Load(cfg, opts)
+	assertNil(t, err)
+
+	assertEqual(t, 90*time.Second, cfg.Timeout)
+	assertSliceEqual(t, []time.Duration{time.Second, 5 * time.Second}, cfg.Backoffs)
+	assertEqual(t, time.Date(2025, 3, 4, 0, 0, 0, 0, time.UTC), cfg.Since)
+
+	opts.Viper.Set("since", "2026-05-06")
+	err = Load(cfg, opts)
+	assertNil(t, err)
+	assertEqual(t, time.Date(2026, 5, 6, 0, 0, 0, 0, time.UTC), cfg.Since)
+}
+
+type PopulateArrays struct {
+	Arr [3]int `config:"\"[1,2,3]\",The array"`
+}
+
+func TestPopulateArrays(t *testing.T) {
+	opts := &Options{Viper: viper.New()}
+	cmd := &cobra.Command{}
+	err := Bind(PopulateArrays{}, cmd, opts)
+	assertNil(t, err)
+
+	cfg := &PopulateArrays{}
+	err = Load(cfg, opts)
+	assertNil(t, err)
+	assertEqual(t, [3]int{1, 2, 3}, cfg.Arr)
+
+	err = cmd.ParseFlags([]string{"--arr", "4,5,6"})
+	assertNil(t, err)
+
+	err = Load(cfg, opts)
+	assertNil(t, err)
+	assertEqual(t, [3]int{4, 5, 6}, cfg.Arr)
+
+	opts.Viper.Set("arr", []int{7, 8})
+	err = Load(cfg, opts)
+	assertErrorIs(t, err, &decodeError{})
+}
+
+type PopulateDecodeError struct {
+	PopulateOverflow int8 `config:"12,The int8 to populate"`
+}
+
+func TestPopulateDecodeError(t *testing.T) {
+	cmd := &cobra.Command{}
+	err := Bind(PopulateDecodeError{}, cmd)
+	assertNil(t, err)
+
+	// Viper reads all integer flags back as an int, so out of range values are
+	// only caught when decoding into the field.
+	cmd.Flags().Lookup("populateoverflow").Value = &overflowValue{}
+
+	err = Load(&PopulateDecodeError{})
+	assertErrorIs(t, err, &decodeError{})
+}
+
+type overflowValue struct{}
+
+func (*overflowValue) String() string   { return "1000" }
+func (*overflowValue) Set(string) error { return nil }
+func (*overflowValue) Type() string     { return "int8" }
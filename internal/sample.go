@@ -0,0 +1,273 @@
+package internal
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"go.yaml.in/yaml/v3"
+)
+
+// sampleNode is a key in a sample config file. Leaves hold a value, while nested
+// structs hold their keys as children, in the order the fields are declared.
+type sampleNode struct {
+	key      string
+	comment  string
+	value    any
+	children []*sampleNode
+}
+
+// GenerateSample returns a config file for obj in the given format, containing every key
+// that would be bound along with its default from the tag. YAML, TOML and env files
+// include the description of each key as a comment. Secrets are left empty.
+func GenerateSample(obj any, format Format, options ...*Options) ([]byte, error) {
+	b, err := defaults(obj, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := &bytes.Buffer{}
+	err = b.writeSample(buf, format)
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// defaults binds obj to a throwaway command, returning a binder that resolves values from
+// the flags alone, so that they come from the tags rather than the environment or a
+// config file.
+func defaults(obj any, options ...*Options) (*Binder, error) {
+	t := reflect.TypeOf(obj)
+	if t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, NewInvalidTypeError(reflect.ValueOf(obj).Kind(), "obj", fmt.Errorf("expected a struct or pointer to a struct"))
+	}
+
+	// Skipped fields are reported by Bind, not again each time the defaults are read.
+	opts := *newBinder(options...).opts
+	opts.Viper = viper.New()
+	opts.OnSkip = nil
+	b := &Binder{opts: &opts, typ: t}
+	if opts.UseStructValues {
+		err := b.structValues(obj)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err := b.processFields("", t, &cobra.Command{})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(b.errs) > 0 {
+		return nil, NewAggregateError(b.errs)
+	}
+
+	v := viper.New()
+	for _, f := range b.fields {
+		err = v.BindPFlag(f.Key, f.Flag)
+		if err != nil {
+			return nil, NewBindError(f.Type.Kind(), f.Key, err)
+		}
+	}
+
+	opts.Viper = v
+	return b, nil
+}
+
+func (b *Binder) writeSample(w io.Writer, format Format) error {
+	root := &sampleNode{}
+	tree := map[string]any{}
+	env := &strings.Builder{}
+	for _, f := range b.fields {
+		v, err := b.resolve(f)
+		if err != nil {
+			return err
+		}
+
+		value := b.plain(f, v)
+		if f.Tag.Secret && value != nil {
+			value = reflect.Zero(reflect.TypeOf(value)).Interface()
+		}
+
+		b.sampleLeaf(root, f, value)
+		b.nest(tree, f.Key, value)
+		if f.Description != "" {
+			fmt.Fprintf(env, "# %s\n", f.Description)
+		}
+
+		fmt.Fprintf(env, "%s=%s\n", b.envName(f), envValue(value))
+	}
+
+	switch format {
+	case FormatYAML:
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		err := enc.Encode(root.yaml())
+		if err != nil {
+			return err
+		}
+
+		return enc.Close()
+	case FormatTOML:
+		return root.toml(w, "")
+	case FormatEnv:
+		_, err := io.WriteString(w, env.String())
+		return err
+	}
+
+	return encode(w, format, tree)
+}
+
+// sampleLeaf adds a field to the tree, creating a node for each struct it is nested in.
+func (b *Binder) sampleLeaf(root *sampleNode, f *Field, value any) {
+	parts := strings.Split(f.Key, b.opts.Separator)
+	n := root
+	for _, p := range parts[:len(parts)-1] {
+		n = n.child(p)
+	}
+
+	n.children = append(n.children, &sampleNode{key: parts[len(parts)-1], comment: f.Description, value: value})
+}
+
+func (n *sampleNode) child(key string) *sampleNode {
+	for _, c := range n.children {
+		if c.key == key && c.children != nil {
+			return c
+		}
+	}
+
+	c := &sampleNode{key: key, children: []*sampleNode{}}
+	n.children = append(n.children, c)
+	return c
+}
+
+func (n *sampleNode) yaml() *yaml.Node {
+	m := &yaml.Node{Kind: yaml.MappingNode}
+	for _, c := range n.children {
+		k := &yaml.Node{Kind: yaml.ScalarNode, Value: c.key}
+		if c.comment != "" {
+			k.HeadComment = "# " + c.comment
+		}
+
+		v := &yaml.Node{}
+		if c.children != nil {
+			v = c.yaml()
+		} else if err := v.Encode(c.value); err != nil {
+			v = &yaml.Node{Kind: yaml.ScalarNode, Value: fmt.Sprint(c.value)}
+		}
+
+		m.Content = append(m.Content, k, v)
+	}
+
+	return m
+}
+
+// toml writes the keys of a node followed by a table for each nested struct. Maps are
+// written as inline tables so that they can keep their comment.
+func (n *sampleNode) toml(w io.Writer, path string) error {
+	tables := []*sampleNode{}
+	for _, c := range n.children {
+		if c.children != nil {
+			tables = append(tables, c)
+			continue
+		}
+
+		value, err := tomlValue(c.value)
+		if err != nil {
+			return err
+		}
+
+		if c.comment != "" {
+			fmt.Fprintf(w, "# %s\n", c.comment)
+		}
+
+		_, err = fmt.Fprintf(w, "%s = %s\n", tomlKey(c.key), value)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, c := range tables {
+		p := tomlKey(c.key)
+		if path != "" {
+			p = path + "." + p
+		}
+
+		_, err := fmt.Fprintf(w, "\n[%s]\n", p)
+		if err != nil {
+			return err
+		}
+
+		err = c.toml(w, p)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// tomlValue formats a single value as TOML by encoding it as the only key of a document.
+func tomlValue(value any) (string, error) {
+	if m, ok := value.(map[string]any); ok {
+		pairs := make([]string, 0, len(m))
+		for _, k := range slices.Sorted(maps.Keys(m)) {
+			v, err := tomlValue(m[k])
+			if err != nil {
+				return "", err
+			}
+
+			pairs = append(pairs, fmt.Sprintf("%s = %s", tomlKey(k), v))
+		}
+
+		return fmt.Sprintf("{%s}", strings.Join(pairs, ", ")), nil
+	}
+
+	if items, ok := value.([]any); ok {
+		values := make([]string, len(items))
+		for i, item := range items {
+			v, err := tomlValue(item)
+			if err != nil {
+				return "", err
+			}
+
+			values[i] = v
+		}
+
+		return fmt.Sprintf("[%s]", strings.Join(values, ", ")), nil
+	}
+
+	if value == nil {
+		return "''", nil
+	}
+
+	s, err := toml.Marshal(map[string]any{"v": value})
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(strings.TrimPrefix(string(s), "v = ")), nil
+}
+
+func tomlKey(key string) string {
+	s, err := toml.Marshal(map[string]any{key: 0})
+	if err != nil {
+		return key
+	}
+
+	return strings.TrimSuffix(string(s), " = 0\n")
+}
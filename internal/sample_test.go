@@ -0,0 +1,103 @@
+package internal
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// Sample.
+type SampleConfig struct {
+	Name     string            `config:"mamba,The name to use"`
+	Timeout  time.Duration     `config:"30s,The timeout"`
+	Tags     []string          `config:"default=[\"a\",\"b\"];desc=The tags"`
+	Labels   map[string]string `config:"default=team=snakes;desc=The labels"`
+	Password string            `config:"default=hunter2;desc=The password;secret"`
+	Server   SampleServer      `config:""`
+}
+
+type SampleServer struct {
+	Host string `config:"localhost,The host to listen on"`
+	Port int    `config:"8080,The port to listen on"`
+}
+
+func TestGenerateSampleYAML(t *testing.T) {
+	t.Setenv("APP_NAME", "from-env")
+
+	out, err := GenerateSample(SampleConfig{}, FormatYAML, &Options{EnvPrefix: "APP"})
+	assertNil(t, err)
+
+	expected := `# The name to use
+name: mamba
+# The timeout
+timeout: 30s
+# The tags
+tags:
+  - a
+  - b
+# The labels
+labels:
+  team: snakes
+# The password
+password: ""
+server:
+  # The host to listen on
+  host: localhost
+  # The port to listen on
+  port: 8080
+`
+	assertEqual(t, expected, string(out))
+}
+
+func TestGenerateSampleTOMLLoads(t *testing.T) {
+	out, err := GenerateSample(&SampleConfig{}, FormatTOML)
+	assertNil(t, err)
+	assertEqual(t, true, strings.Contains(string(out), "# The port to listen on\nport = 8080\n"))
+	assertEqual(t, true, strings.Contains(string(out), "labels = {team = 'snakes'}\n"))
+
+	v := viper.New()
+	v.SetConfigType("toml")
+	err = v.ReadConfig(bytes.NewReader(out))
+	assertNil(t, err)
+
+	assertEqual(t, "localhost", v.GetString("server.host"))
+	assertEqual(t, "snakes", v.GetStringMapString("labels")["team"])
+}
+
+func TestGenerateSampleInvalidType(t *testing.T) {
+	_, err := GenerateSample("mamba", FormatYAML)
+	assertErrorIs(t, err, &invalidTypeError{})
+}
+
+func TestConfigInitCommand(t *testing.T) {
+	root := &cobra.Command{Use: "app"}
+	err := Bind(SampleConfig{}, root, &Options{Viper: viper.New(), ConfigCommand: true})
+	assertNil(t, err)
+
+	path := filepath.Join(t.TempDir(), "app.json")
+	root.SetArgs([]string{"config", "init", path})
+	err = root.Execute()
+	assertNil(t, err)
+
+	contents, err := os.ReadFile(path)
+	assertNil(t, err)
+	assertEqual(t, true, strings.Contains(string(contents), "\"port\": 8080"))
+
+	root.SetArgs([]string{"config", "init", path})
+	err = root.Execute()
+	assertError(t, err)
+
+	root.SetArgs([]string{"config", "init", path, "--force", "--format", "yaml"})
+	err = root.Execute()
+	assertNil(t, err)
+
+	contents, err = os.ReadFile(path)
+	assertNil(t, err)
+	assertEqual(t, true, strings.Contains(string(contents), "port: 8080"))
+}
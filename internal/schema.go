@@ -0,0 +1,204 @@
+package internal
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const schemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// JSONSchema returns a JSON Schema (draft 2020-12) describing the config files accepted
+// for obj. Every bound key is described by a property with a type based on its Go type,
+// and the default and description from its tag. Nested structs become nested objects,
+// and validation rules are translated into the equivalent keywords where possible.
+func JSONSchema(obj any, options ...*Options) ([]byte, error) {
+	b, err := defaults(obj, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	root := object()
+	root["$schema"] = schemaDraft
+	if b.typ.Name() != "" {
+		root["title"] = b.typ.Name()
+	}
+
+	for _, f := range b.fields {
+		s := b.schemaType(f.Type, f.Tag)
+		if f.Description != "" {
+			s["description"] = f.Description
+		}
+
+		if f.Tag.Secret {
+			s["writeOnly"] = true
+		} else if f.Tag.Default != "" {
+			v, err := b.resolve(f)
+			if err != nil {
+				return nil, err
+			}
+
+			s["default"] = b.plain(f, v)
+		}
+
+		b.addProperty(root, f.Key, s, schemaRules(s, f.Type, f.Tag.Rules))
+	}
+
+	return json.MarshalIndent(root, "", "  ")
+}
+
+func object() map[string]any {
+	return map[string]any{"type": "object", "properties": map[string]any{}}
+}
+
+// addProperty adds the schema for a key to an object, nesting it within an object for
+// each struct it is in.
+func (b *Binder) addProperty(root map[string]any, key string, s map[string]any, required bool) {
+	parts := strings.Split(key, b.opts.Separator)
+	parent := root
+	for _, p := range parts[:len(parts)-1] {
+		props := parent["properties"].(map[string]any)
+		child, ok := props[p].(map[string]any)
+		if !ok {
+			child = object()
+			props[p] = child
+		}
+
+		parent = child
+	}
+
+	name := parts[len(parts)-1]
+	parent["properties"].(map[string]any)[name] = s
+	if required {
+		r, _ := parent["required"].([]string)
+		parent["required"] = append(r, name)
+	}
+}
+
+// schemaType returns the schema for a Go type. Durations, times and custom types are all
+// written as strings in config files.
+func (b *Binder) schemaType(t reflect.Type, tag *Tag) map[string]any {
+	switch {
+	case t == timeType:
+		s := map[string]any{"type": "string"}
+		if b.layout(tag) == time.RFC3339 {
+			s["format"] = "date-time"
+		}
+
+		return s
+	case t == durationType, b.isLeaf(t):
+		return map[string]any{"type": "string"}
+	case isBytes(t):
+		encoding := "base64"
+		if tag.Encoding == encodingHex {
+			encoding = "base16"
+		}
+
+		return map[string]any{"type": "string", "contentEncoding": encoding}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return map[string]any{"type": "integer"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer", "minimum": 0}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]any{"type": "array", "items": b.schemaType(t.Elem(), tag)}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": b.schemaType(t.Elem(), tag)}
+	case reflect.Ptr:
+		return b.schemaType(t.Elem(), tag)
+	case reflect.Struct:
+		// The elements of a slice of structs, described by the tags of their fields.
+		s := object()
+		b.walkFields("", reflect.New(t).Elem(), func(n string, t *Tag, field reflect.StructField, _ reflect.Value) error {
+			p := b.schemaType(field.Type, t)
+			if t.Description != "" {
+				p["description"] = t.Description
+			}
+
+			b.addProperty(s, n, p, schemaRules(p, field.Type, t.Rules))
+			return nil
+		})
+
+		return s
+	}
+
+	return map[string]any{}
+}
+
+// schemaRules adds the keywords equivalent to the validation rules of a field to its
+// schema, returning whether the field is required.
+func schemaRules(s map[string]any, t reflect.Type, rules []Rule) bool {
+	required := false
+	items, _ := s["items"].(map[string]any)
+	if items == nil {
+		items = s
+	}
+
+	for _, r := range rules {
+		switch r.Name {
+		case "required":
+			required = true
+		case "min", "max", "len":
+			if t == durationType {
+				continue
+			}
+
+			keywords := map[string]string{"min": "minimum", "max": "maximum"}
+			switch s["type"] {
+			case "string":
+				keywords = map[string]string{"min": "minLength", "max": "maxLength"}
+			case "array":
+				keywords = map[string]string{"min": "minItems", "max": "maxItems"}
+			case "object":
+				keywords = map[string]string{"min": "minProperties", "max": "maxProperties"}
+			}
+
+			if r.Name == "len" {
+				s[keywords["min"]], s[keywords["max"]] = r.num, r.num
+			} else {
+				s[keywords[r.Name]] = r.num
+			}
+		case "oneof":
+			items["enum"] = enumValues(items["type"], r.values)
+		case "regex":
+			items["pattern"] = r.Arg
+		}
+	}
+
+	return required
+}
+
+// enumValues converts the choices of a `oneof` rule to the type of the schema, so that
+// e.g. the choices for an integer are numbers rather than strings.
+func enumValues(typ any, choices []string) []any {
+	values := make([]any, len(choices))
+	for i, c := range choices {
+		values[i] = c
+		switch typ {
+		case "integer":
+			if n, err := strconv.ParseInt(c, 10, 64); err == nil {
+				values[i] = n
+			}
+		case "number":
+			if n, err := strconv.ParseFloat(c, 64); err == nil {
+				values[i] = n
+			}
+		case "boolean":
+			if v, err := strconv.ParseBool(c); err == nil {
+				values[i] = v
+			}
+		}
+	}
+
+	return values
+}
@@ -0,0 +1,79 @@
+package internal
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+// JSON Schema.
+type SchemaConfig struct {
+	Name     string            `config:"mamba,The name to use,false,,,required len=5"`
+	Level    string            `config:"default=info;desc=The log level;oneof=debug|info"`
+	Port     uint16            `config:"default=8080;desc=The port;min=1"`
+	Ratio    float64           `config:"0.5,The ratio"`
+	Timeout  time.Duration     `config:"30s,The timeout"`
+	Started  time.Time         `config:",When it started"`
+	Tags     []string          `config:"default=[\"a\"];desc=The tags;max=3;regex=^[a-z]+$"`
+	Labels   map[string]string `config:",The labels"`
+	Password string            `config:"default=hunter2;desc=The password;secret"`
+	Server   SchemaServer      `config:""`
+}
+
+type SchemaServer struct {
+	Host string `config:"localhost,The host,false,,,required"`
+}
+
+func TestJSONSchema(t *testing.T) {
+	out, err := JSONSchema(SchemaConfig{}, &Options{EnvPrefix: "APP"})
+	assertNil(t, err)
+
+	var s map[string]any
+	err = json.Unmarshal(out, &s)
+	assertNil(t, err)
+
+	assertEqual(t, schemaDraft, s["$schema"].(string))
+	assertEqual(t, "SchemaConfig", s["title"].(string))
+	assertSliceEqual(t, []any{"name"}, s["required"].([]any))
+
+	props := s["properties"].(map[string]any)
+	prop := func(name string) map[string]any { return props[name].(map[string]any) }
+
+	assertEqual(t, "string", prop("name")["type"].(string))
+	assertEqual(t, "mamba", prop("name")["default"].(string))
+	assertEqual(t, "The name to use", prop("name")["description"].(string))
+	assertEqual(t, 5.0, prop("name")["minLength"].(float64))
+	assertEqual(t, 5.0, prop("name")["maxLength"].(float64))
+
+	assertSliceEqual(t, []any{"debug", "info"}, prop("level")["enum"].([]any))
+	assertEqual(t, "The log level", prop("level")["description"].(string))
+
+	assertEqual(t, "integer", prop("port")["type"].(string))
+	assertEqual(t, 1.0, prop("port")["minimum"].(float64))
+	assertEqual(t, 8080.0, prop("port")["default"].(float64))
+
+	assertEqual(t, "number", prop("ratio")["type"].(string))
+	assertEqual(t, "30s", prop("timeout")["default"].(string))
+	assertEqual(t, "date-time", prop("started")["format"].(string))
+
+	tags := prop("tags")
+	assertEqual(t, "array", tags["type"].(string))
+	assertEqual(t, 3.0, tags["maxItems"].(float64))
+	assertEqual(t, "^[a-z]+$", tags["items"].(map[string]any)["pattern"].(string))
+
+	assertEqual(t, "string", prop("labels")["additionalProperties"].(map[string]any)["type"].(string))
+
+	_, hasDefault := prop("password")["default"]
+	assertEqual(t, false, hasDefault)
+	assertEqual(t, true, prop("password")["writeOnly"].(bool))
+
+	server := prop("server")
+	assertEqual(t, "object", server["type"].(string))
+	assertSliceEqual(t, []any{"host"}, server["required"].([]any))
+	assertEqual(t, "localhost", server["properties"].(map[string]any)["host"].(map[string]any)["default"].(string))
+}
+
+func TestJSONSchemaInvalidType(t *testing.T) {
+	_, err := JSONSchema(42)
+	assertErrorIs(t, err, &invalidTypeError{})
+}
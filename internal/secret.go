@@ -0,0 +1,65 @@
+package internal
+
+import (
+	"os"
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+// redacted replaces the value of secret fields wherever values are shown.
+const redacted = "********"
+
+// secretValue wraps the value of a secret flag. pflag decides whether to show a default
+// based on the concrete type of the value, so wrapping it stops the emptied default of a
+// secret being shown as `(default "")` in the help output.
+type secretValue struct {
+	pflag.Value
+}
+
+// hideDefault stops the default of a secret flag from being shown in the help output.
+func hideDefault(fl *pflag.Flag) {
+	fl.Value = &secretValue{fl.Value}
+	fl.DefValue = ""
+}
+
+// hasSecretFiles reports whether any secrets could be read from a `_FILE` env var.
+func (b *Binder) hasSecretFiles() bool {
+	for _, f := range b.fields {
+		if f.Tag.Secret && f.Env != "" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// readSecretFiles sets each secret from the file named by its env var with a `_FILE`
+// suffix, in the style of Docker and Kubernetes secrets. A file is only read when neither
+// the flag nor the env var itself has been set, so it takes precedence over config files
+// and defaults.
+func (b *Binder) readSecretFiles() error {
+	for _, f := range b.fields {
+		if !f.Tag.Secret || f.Env == "" || (f.Flag != nil && f.Flag.Changed) {
+			continue
+		}
+
+		path := os.Getenv(secretFileEnv(f.Env))
+		if envSet(f.Env) || path == "" {
+			continue
+		}
+
+		contents, err := os.ReadFile(path)
+		if err != nil {
+			return NewConfigError(path, f.Key, err)
+		}
+
+		b.viper().Set(f.Key, strings.TrimRight(string(contents), "\r\n"))
+	}
+
+	return nil
+}
+
+func secretFileEnv(env string) string {
+	return env + "_FILE"
+}
@@ -0,0 +1,120 @@
+package internal
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// Secrets.
+type SecretHidden struct {
+	Password string `config:"default=hunter2;desc=The database password;secret"`
+	Pin      int    `config:"default=1234;desc=The pin;secret"`
+	User     string `config:"admin,The database user"`
+}
+
+func TestSecretHidesDefaultInHelp(t *testing.T) {
+	cmd := &cobra.Command{}
+	err := Bind(SecretHidden{}, cmd, &Options{Viper: viper.New()})
+	assertNil(t, err)
+
+	usage := cmd.Flags().FlagUsages()
+	assertEqual(t, false, strings.Contains(usage, "hunter2"))
+	assertEqual(t, false, strings.Contains(usage, "1234"))
+	assertEqual(t, true, strings.Contains(usage, "admin"))
+
+	p, err := cmd.Flags().GetInt("pin")
+	assertNil(t, err)
+	assertEqual(t, 1234, p)
+}
+
+func TestSecretMaskedInExplainAndDump(t *testing.T) {
+	v := viper.New()
+	cmd := &cobra.Command{}
+	err := Bind(SecretHidden{}, cmd, &Options{Viper: v})
+	assertNil(t, err)
+
+	for _, e := range Explain(cmd) {
+		if e.Key == "password" {
+			assertEqual(t, redacted, e.Value.(string))
+		}
+	}
+
+	out := &bytes.Buffer{}
+	err = Dump(out, FormatEnv, cmd)
+	assertNil(t, err)
+	assertEqual(t, false, strings.Contains(out.String(), "hunter2"))
+
+	cfg := &SecretHidden{}
+	err = Load(cfg, &Options{Viper: v})
+	assertNil(t, err)
+	assertEqual(t, "hunter2", cfg.Password)
+}
+
+type SecretFromFile struct {
+	Password string `config:"default=;desc=The database password;secret;env=DB_PASSWORD"`
+}
+
+func TestSecretReadFromFile(t *testing.T) {
+	path := writeConfig(t, t.TempDir(), "password", "s3cret\n")
+	t.Setenv("DB_PASSWORD_FILE", path)
+
+	opts := &Options{Viper: viper.New()}
+	cfg := &SecretFromFile{}
+	cmd := &cobra.Command{Use: "app", RunE: func(*cobra.Command, []string) error { return Load(cfg, opts) }}
+	err := Bind(SecretFromFile{}, cmd, opts)
+	assertNil(t, err)
+
+	cmd.SetArgs([]string{})
+	err = cmd.Execute()
+	assertNil(t, err)
+	assertEqual(t, "s3cret", cfg.Password)
+}
+
+func TestSecretEnvBeatsFile(t *testing.T) {
+	path := writeConfig(t, t.TempDir(), "password", "s3cret\n")
+	t.Setenv("DB_PASSWORD_FILE", path)
+	t.Setenv("DB_PASSWORD", "from-env")
+
+	opts := &Options{Viper: viper.New()}
+	cfg := &SecretFromFile{}
+	cmd := &cobra.Command{Use: "app", RunE: func(*cobra.Command, []string) error { return Load(cfg, opts) }}
+	err := Bind(SecretFromFile{}, cmd, opts)
+	assertNil(t, err)
+
+	cmd.SetArgs([]string{})
+	err = cmd.Execute()
+	assertNil(t, err)
+	assertEqual(t, "from-env", cfg.Password)
+}
+
+func TestSecretFileMissing(t *testing.T) {
+	t.Setenv("DB_PASSWORD_FILE", "/does/not/exist")
+
+	cmd := &cobra.Command{Use: "app", Run: func(*cobra.Command, []string) {}}
+	err := Bind(SecretFromFile{}, cmd, &Options{Viper: viper.New()})
+	assertNil(t, err)
+
+	cmd.SetArgs([]string{})
+	err = cmd.Execute()
+	assertErrorIs(t, err, &configError{})
+}
+
+type SecretValidation struct {
+	Token string `config:"default=;desc=The token;secret;regex=^tok_"`
+}
+
+func TestSecretMaskedInViolations(t *testing.T) {
+	err := Validate(SecretValidation{Token: "hunter2"})
+
+	var verr *validationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("expected a validation error but got %v", err)
+	}
+
+	assertEqual(t, false, strings.Contains(err.Error(), "hunter2"))
+}
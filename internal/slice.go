@@ -0,0 +1,141 @@
+package internal
+
+import (
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+// Encodings for byte slices, set with the `encoding` key of the named tag form.
+const (
+	encodingBase64 = "base64"
+	encodingHex    = "hex"
+)
+
+// parseSlice parses a slice default written as JSON, e.g. `[1,2,3]`.
+func parseSlice[T any](def string) ([]T, error) {
+	var s []T
+	if def == "" {
+		return s, nil
+	}
+
+	err := json.Unmarshal([]byte(def), &s)
+	return s, err
+}
+
+// addSlice binds a slice using one of the slice flags built in to pflag.
+func addSlice[T any](add func(name, shorthand string, value []T, usage string) *[]T, n string, t *Tag) error {
+	s, err := parseSlice[T](t.Default)
+	if err != nil {
+		return err
+	}
+
+	add(n, t.Shorthand, s, t.Description)
+	return nil
+}
+
+// addSliceValue binds a slice of an element type pflag has no slice flag for.
+func addSliceValue[T any](f *pflag.FlagSet, n string, t *Tag, typ string, parse func(string) (T, error)) error {
+	s, err := parseSlice[T](t.Default)
+	if err != nil {
+		return err
+	}
+
+	f.VarP(&sliceValue[T]{value: &s, parse: parse, typ: typ}, n, t.Shorthand, t.Description)
+	return nil
+}
+
+func parseInt[T ~int8 | ~int16](bits int) func(string) (T, error) {
+	return func(s string) (T, error) {
+		i, err := strconv.ParseInt(strings.TrimSpace(s), 0, bits)
+		return T(i), err
+	}
+}
+
+func parseUint[T ~uint8 | ~uint16 | ~uint32 | ~uint64](bits int) func(string) (T, error) {
+	return func(s string) (T, error) {
+		i, err := strconv.ParseUint(strings.TrimSpace(s), 0, bits)
+		return T(i), err
+	}
+}
+
+// sliceValue is a slice flag for any element type. Like the slice flags built in to pflag
+// it accepts comma separated values, with the first use of the flag replacing the default
+// and any later uses appending to it.
+type sliceValue[T any] struct {
+	value   *[]T
+	parse   func(string) (T, error)
+	typ     string
+	changed bool
+}
+
+func (s *sliceValue[T]) Set(val string) error {
+	items := []T{}
+	if val != "" {
+		record, err := csv.NewReader(strings.NewReader(val)).Read()
+		if err != nil {
+			return err
+		}
+
+		for _, r := range record {
+			item, err := s.parse(r)
+			if err != nil {
+				return err
+			}
+
+			items = append(items, item)
+		}
+	}
+
+	if s.changed {
+		*s.value = append(*s.value, items...)
+	} else {
+		*s.value = items
+	}
+
+	s.changed = true
+	return nil
+}
+
+func (s *sliceValue[T]) String() string {
+	items := make([]string, len(*s.value))
+	for i, item := range *s.value {
+		items[i] = fmt.Sprint(item)
+	}
+
+	return "[" + strings.Join(items, ",") + "]"
+}
+
+func (s *sliceValue[T]) Type() string {
+	return s.typ
+}
+
+// isBytes reports whether t is a byte slice, which is bound as a single encoded value
+// rather than a list. Byte slices that parse themselves, such as net.IP, are custom types.
+func isBytes(t reflect.Type) bool {
+	return t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8 && !isCustom(t)
+}
+
+// decodeBytes decodes a byte slice written as base64, or as hex for `encoding=hex`.
+func decodeBytes(s string, encoding string) ([]byte, error) {
+	if encoding == encodingHex {
+		return hex.DecodeString(strings.TrimSpace(s))
+	}
+
+	return base64.StdEncoding.DecodeString(strings.TrimSpace(s))
+}
+
+func encodeBytes(b []byte, encoding string) string {
+	if encoding == encodingHex {
+		return hex.EncodeToString(b)
+	}
+
+	return base64.StdEncoding.EncodeToString(b)
+}
@@ -0,0 +1,57 @@
+package internal
+
+import (
+	"io"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// Strict.
+type StrictSkipped struct {
+	Name     string      `config:"mamba,The name"`
+	Values   []complex64 `config:",Some complex numbers"`
+	Untagged string
+	hidden   string `config:"hidden,A hidden field"`
+}
+
+func TestSkippedFieldsReported(t *testing.T) {
+	skipped := map[string]string{}
+	opts := &Options{Viper: viper.New(), OnSkip: func(key, reason string) { skipped[key] = reason }}
+	cmd := &cobra.Command{}
+	err := Bind(StrictSkipped{}, cmd, opts)
+	assertNil(t, err)
+
+	assertEqual(t, 2, len(skipped))
+	assertEqual(t, "no config tag", skipped["untagged"])
+	assertEqual(t, "invalid/unsupported type \"slice\" for \"values\": unsupported element type complex64", skipped["values"])
+	assertEqual(t, true, cmd.Flags().Lookup("values") == nil)
+}
+
+func TestStrictReturnsUnsupportedSlices(t *testing.T) {
+	err := Bind(StrictSkipped{}, &cobra.Command{}, &Options{Viper: viper.New(), Strict: true})
+	assertErrorIs(t, err, &invalidTypeError{})
+}
+
+func TestSkippedFieldsNotReportedBySamples(t *testing.T) {
+	skipped := 0
+	opts := &Options{Viper: viper.New(), ConfigCommand: true, OnSkip: func(string, string) { skipped++ }}
+	root := &cobra.Command{Use: "app"}
+	err := Bind(StrictSkipped{}, root, opts)
+	assertNil(t, err)
+	assertEqual(t, 2, skipped)
+
+	_, err = GenerateSample(StrictSkipped{}, FormatYAML, opts)
+	assertNil(t, err)
+
+	_, err = JSONSchema(StrictSkipped{}, opts)
+	assertNil(t, err)
+
+	root.SetOut(io.Discard)
+	root.SetArgs([]string{"config", "init"})
+	err = root.Execute()
+	assertNil(t, err)
+
+	assertEqual(t, 2, skipped)
+}
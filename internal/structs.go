@@ -0,0 +1,231 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// isStructSlice reports whether t is a slice of structs, or of pointers to structs, that
+// are bound as a list of objects rather than as a single value.
+func (b *Binder) isStructSlice(t reflect.Type) bool {
+	if t.Kind() != reflect.Slice {
+		return false
+	}
+
+	e := t.Elem()
+	if e.Kind() == reflect.Ptr {
+		e = e.Elem()
+	}
+
+	return e.Kind() == reflect.Struct && !b.isLeaf(e) && !b.isLeaf(t.Elem())
+}
+
+// jsonValue is a flag holding a list of objects as JSON, e.g. `[{"host":"a"}]`.
+type jsonValue struct {
+	raw string
+}
+
+func (v *jsonValue) Set(s string) error {
+	var items []map[string]any
+	err := json.Unmarshal([]byte(s), &items)
+	if err != nil {
+		return fmt.Errorf("expected a json list of objects: %w", err)
+	}
+
+	v.raw = s
+	return nil
+}
+
+func (v *jsonValue) String() string {
+	return v.raw
+}
+
+func (v *jsonValue) Type() string {
+	return "json"
+}
+
+// processStructSlice binds a slice of structs as a single flag taking the whole list as
+// JSON. With the `indexed` tag key, hidden flags such as `--upstreams.0.host` are also
+// bound for each key of the first elements of the list.
+func (b *Binder) processStructSlice(n string, t *Tag, field reflect.StructField, cmd *cobra.Command) error {
+	k := field.Type.Kind()
+	f := b.flags(cmd, t)
+
+	v := &jsonValue{}
+	if t.Default != "" {
+		err := v.Set(t.Default)
+		if err != nil {
+			return NewParseError(t.Default, k, n, err)
+		}
+	}
+
+	if t.Indexed > 0 {
+		t.Description = fmt.Sprintf("%s (elements can also be set with --%s%s<index>%s<key>)", t.Description, n, b.opts.Separator, b.opts.Separator)
+	}
+
+	f.VarP(v, n, t.Shorthand, t.Description)
+
+	keys, err := b.elementKeys(field.Type)
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i < t.Indexed; i++ {
+		for _, e := range keys {
+			name := b.elementPrefix(n, i) + b.opts.Separator + e.key
+			f.String(name, "", e.tag.Description)
+
+			fl := f.Lookup(name)
+			fl.Hidden = true
+			if e.bool {
+				fl.NoOptDefVal = "true"
+			}
+
+			err = b.viper().BindPFlag(indexedKey(n, i, e.key), fl)
+			if err != nil {
+				return NewBindError(k, name, err)
+			}
+
+			if env := b.env(name, &Tag{}); env != "" {
+				err = b.viper().BindEnv(indexedKey(n, i, e.key), env)
+				if err != nil {
+					return NewBindError(k, name, err)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// indexedKey is the key an indexed flag is bound to. It differs from the flag name so
+// that viper does not also find the element in a config file under the same key.
+func indexedKey(n string, i int, key string) string {
+	return fmt.Sprintf("%s[%d].%s", n, i, key)
+}
+
+type elementKey struct {
+	key  string
+	tag  *Tag
+	bool bool
+
+	// path is the key joined with dots, as used by the viper instance of each element.
+	path string
+}
+
+// elementKeys returns the keys of each element of a slice of structs.
+func (b *Binder) elementKeys(t reflect.Type) ([]elementKey, error) {
+	keys := []elementKey{}
+	err := b.walkFields("", reflect.New(elementType(t)).Elem(), func(n string, t *Tag, field reflect.StructField, _ reflect.Value) error {
+		keys = append(keys, elementKey{key: n, tag: t, bool: field.Type.Kind() == reflect.Bool})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	i := 0
+	err = b.elementBinder(nil).walkFields("", reflect.New(elementType(t)).Elem(), func(n string, _ *Tag, _ reflect.StructField, _ reflect.Value) error {
+		keys[i].path = n
+		i++
+		return nil
+	})
+
+	return keys, err
+}
+
+// elementBinder returns a binder for the elements of a slice of structs. Elements are
+// populated from a viper instance of their own, which always nests keys with dots, so
+// the separator is ignored.
+func (b *Binder) elementBinder(v *viper.Viper) *Binder {
+	opts := *b.opts
+	opts.Separator = "."
+	opts.Viper = v
+
+	return &Binder{opts: &opts}
+}
+
+func elementType(t reflect.Type) reflect.Type {
+	e := t.Elem()
+	if e.Kind() == reflect.Ptr {
+		return e.Elem()
+	}
+
+	return e
+}
+
+// populateStructs sets a slice of structs from the list resolved by viper, with any
+// indexed flags or env vars applied on top. Each element is populated with its own viper
+// instance, so keys missing from an element take the defaults from its tags.
+func (b *Binder) populateStructs(n string, t *Tag, raw any, v reflect.Value) error {
+	items, err := toSlice(raw)
+	if err != nil {
+		return err
+	}
+
+	keys, err := b.elementKeys(v.Type())
+	if err != nil {
+		return err
+	}
+
+	count := len(items)
+	for i := 0; i < t.Indexed; i++ {
+		for _, e := range keys {
+			if b.viper().IsSet(indexedKey(n, i, e.key)) {
+				count = max(count, i+1)
+			}
+		}
+	}
+
+	et := elementType(v.Type())
+	s := reflect.MakeSlice(v.Type(), count, count)
+	for i := 0; i < count; i++ {
+		ev := viper.New()
+		if i < len(items) {
+			m, err := toMap(items[i])
+			if err != nil {
+				return err
+			}
+
+			err = ev.MergeConfigMap(m)
+			if err != nil {
+				return err
+			}
+		}
+
+		for _, e := range keys {
+			if e.tag.Default != "" {
+				ev.SetDefault(e.path, e.tag.Default)
+			}
+
+			if i < t.Indexed && b.viper().IsSet(indexedKey(n, i, e.key)) {
+				ev.Set(e.path, b.viper().Get(indexedKey(n, i, e.key)))
+			}
+		}
+
+		e := reflect.New(et)
+		err = b.elementBinder(ev).Populate(e.Interface())
+		if err != nil {
+			return fmt.Errorf("element %d: %w", i, err)
+		}
+
+		if v.Type().Elem().Kind() == reflect.Ptr {
+			s.Index(i).Set(e)
+		} else {
+			s.Index(i).Set(e.Elem())
+		}
+	}
+
+	v.Set(s)
+	return nil
+}
+
+// elementPrefix is the key prefix for the fields of an element of a slice of structs.
+func (b *Binder) elementPrefix(n string, i int) string {
+	return strings.Join([]string{n, fmt.Sprint(i)}, b.opts.Separator)
+}
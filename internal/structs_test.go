@@ -0,0 +1,179 @@
+package internal
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// Slices of structs.
+type StructSliceConfig struct {
+	Config    string              `config:"default=;desc=The config file;configfile"`
+	Upstreams []StructSliceEntry  `config:"default=[{\"host\":\"a.local\"}];desc=The upstreams;indexed=3"`
+	Backups   []*StructSliceEntry `config:",The backups"`
+}
+
+type StructSliceEntry struct {
+	Host    string        `config:",The host,false,,,required"`
+	Port    int           `config:"80,The port,false,,,max=65535"`
+	Weight  float64       `config:"1,The weight"`
+	Timeout time.Duration `config:"5s,The timeout"`
+	Enabled bool          `config:"true,Whether it is enabled"`
+}
+
+func loadStructSlices(t *testing.T, args ...string) (*StructSliceConfig, error) {
+	opts := &Options{Viper: viper.New(), EnvPrefix: "APP"}
+	cfg := &StructSliceConfig{}
+	cmd := &cobra.Command{Use: "app", RunE: func(*cobra.Command, []string) error { return Load(cfg, opts) }}
+	err := Bind(StructSliceConfig{}, cmd, opts)
+	assertNil(t, err)
+
+	cmd.SetArgs(args)
+	return cfg, cmd.Execute()
+}
+
+func TestStructSliceDefault(t *testing.T) {
+	cfg, err := loadStructSlices(t)
+	assertNil(t, err)
+
+	assertEqual(t, 1, len(cfg.Upstreams))
+	assertEqual(t, "a.local", cfg.Upstreams[0].Host)
+	assertEqual(t, 80, cfg.Upstreams[0].Port)
+	assertEqual(t, 5*time.Second, cfg.Upstreams[0].Timeout)
+	assertEqual(t, true, cfg.Upstreams[0].Enabled)
+	assertEqual(t, 0, len(cfg.Backups))
+}
+
+func TestStructSliceFromConfigFile(t *testing.T) {
+	path := writeConfig(t, t.TempDir(), "app.yaml", "upstreams:\n  - host: b.local\n    port: 8080\n    timeout: 1m\n  - host: c.local\n    weight: 0.5\nbackups:\n  - host: d.local\n")
+
+	cfg, err := loadStructSlices(t, "--config", path)
+	assertNil(t, err)
+
+	assertEqual(t, 2, len(cfg.Upstreams))
+	assertEqual(t, 8080, cfg.Upstreams[0].Port)
+	assertEqual(t, time.Minute, cfg.Upstreams[0].Timeout)
+	assertEqual(t, "c.local", cfg.Upstreams[1].Host)
+	assertEqual(t, 80, cfg.Upstreams[1].Port)
+	assertEqual(t, 0.5, cfg.Upstreams[1].Weight)
+	assertEqual(t, "d.local", cfg.Backups[0].Host)
+}
+
+func TestStructSliceFromTOMLFile(t *testing.T) {
+	path := writeConfig(t, t.TempDir(), "app.toml", "[[upstreams]]\nhost = 'b.local'\n\n[[upstreams]]\nhost = 'c.local'\nport = 81\n")
+
+	cfg, err := loadStructSlices(t, "--config", path)
+	assertNil(t, err)
+
+	assertEqual(t, 2, len(cfg.Upstreams))
+	assertEqual(t, 81, cfg.Upstreams[1].Port)
+}
+
+func TestStructSliceFlagAndEnv(t *testing.T) {
+	cfg, err := loadStructSlices(t, "--upstreams", `[{"host":"e.local","port":90}]`)
+	assertNil(t, err)
+	assertEqual(t, "e.local", cfg.Upstreams[0].Host)
+	assertEqual(t, 90, cfg.Upstreams[0].Port)
+
+	t.Setenv("APP_UPSTREAMS", `[{"host":"f.local"},{"host":"g.local"}]`)
+	cfg, err = loadStructSlices(t)
+	assertNil(t, err)
+	assertEqual(t, 2, len(cfg.Upstreams))
+	assertEqual(t, "g.local", cfg.Upstreams[1].Host)
+}
+
+func TestStructSliceIndexedFlags(t *testing.T) {
+	path := writeConfig(t, t.TempDir(), "app.yaml", "upstreams:\n  - host: b.local\n  - host: c.local\n")
+	t.Setenv("APP_UPSTREAMS_0_PORT", "8443")
+
+	cfg, err := loadStructSlices(t, "--config", path, "--upstreams.1.host", "h.local", "--upstreams.2.host", "i.local", "--upstreams.2.enabled=false")
+	assertNil(t, err)
+
+	assertEqual(t, 3, len(cfg.Upstreams))
+	assertEqual(t, "b.local", cfg.Upstreams[0].Host)
+	assertEqual(t, 8443, cfg.Upstreams[0].Port)
+	assertEqual(t, "h.local", cfg.Upstreams[1].Host)
+	assertEqual(t, "i.local", cfg.Upstreams[2].Host)
+	assertEqual(t, 80, cfg.Upstreams[2].Port)
+	assertEqual(t, false, cfg.Upstreams[2].Enabled)
+}
+
+type StructSliceSeparator struct {
+	Upstreams []StructSliceNested `config:"default=[{\"host\":\"a\",\"tls\":{\"cert\":\"x\"}}];desc=The upstreams;indexed=2"`
+}
+
+type StructSliceNested struct {
+	Host string               `config:",The host"`
+	TLS  StructSliceNestedTLS `config:""`
+}
+
+type StructSliceNestedTLS struct {
+	Cert string `config:"/etc/cert,The cert"`
+	Key  string `config:"/etc/key,The key"`
+}
+
+func TestStructSliceNestedWithSeparator(t *testing.T) {
+	for _, sep := range []string{".", "-"} {
+		opts := &Options{Viper: viper.New(), Separator: sep}
+		cfg := &StructSliceSeparator{}
+		cmd := &cobra.Command{Use: "app", RunE: func(*cobra.Command, []string) error { return Load(cfg, opts) }}
+		err := Bind(StructSliceSeparator{}, cmd, opts)
+		assertNil(t, err)
+
+		cmd.SetArgs([]string{"--upstreams" + sep + "1" + sep + "tls" + sep + "key", "/tmp/key"})
+		err = cmd.Execute()
+		assertNil(t, err)
+
+		assertEqual(t, 2, len(cfg.Upstreams))
+		assertEqual(t, "a", cfg.Upstreams[0].Host)
+		assertEqual(t, "x", cfg.Upstreams[0].TLS.Cert)
+		assertEqual(t, "/etc/key", cfg.Upstreams[0].TLS.Key)
+		assertEqual(t, "/etc/cert", cfg.Upstreams[1].TLS.Cert)
+		assertEqual(t, "/tmp/key", cfg.Upstreams[1].TLS.Key)
+	}
+}
+
+func TestStructSliceValidatesElements(t *testing.T) {
+	_, err := loadStructSlices(t, "--upstreams", `[{"host":"a.local"},{"port":70000}]`)
+
+	var verr *validationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("expected a validation error but got %v", err)
+	}
+
+	keys := []string{}
+	for _, v := range verr.Violations {
+		keys = append(keys, v.Key+":"+v.Rule)
+	}
+
+	assertSliceEqual(t, []string{"upstreams.1.host:required", "upstreams.1.port:max"}, keys)
+}
+
+func TestStructSliceInvalidDefault(t *testing.T) {
+	type Invalid struct {
+		Upstreams []StructSliceEntry `config:"default={\"host\":\"a\"};desc=Not a list"`
+	}
+
+	err := Bind(Invalid{}, &cobra.Command{}, &Options{Viper: viper.New()})
+	assertErrorIs(t, err, &parseError{})
+}
+
+func TestStructSliceDump(t *testing.T) {
+	cmd := &cobra.Command{}
+	err := Bind(StructSliceConfig{}, cmd, &Options{Viper: viper.New()})
+	assertNil(t, err)
+
+	out := &bytes.Buffer{}
+	err = Dump(out, FormatYAML, cmd)
+	assertNil(t, err)
+	assertEqual(t, true, strings.Contains(out.String(), "upstreams:\n  - enabled: true\n    host: a.local\n    port: 80\n"))
+
+	sample, err := GenerateSample(StructSliceConfig{}, FormatTOML)
+	assertNil(t, err)
+	assertEqual(t, true, strings.Contains(string(sample), "upstreams = [{enabled = true, host = 'a.local', port = 80, timeout = '5s', weight = 1.0}]\n"))
+}
@@ -0,0 +1,102 @@
+package internal
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// Struct values.
+type StructValuesConfig struct {
+	Workers int               `config:"1,The number of workers"`
+	Name    string            `config:"mamba,The name to use"`
+	Ratio   float64           `config:"0.5,The ratio"`
+	Timeout time.Duration     `config:"30s,The timeout"`
+	Tags    []string          `config:",The tags"`
+	Labels  map[string]string `config:",The labels"`
+	IP      net.IP            `config:",The IP to bind to"`
+	Server  *StructValuesHost `config:""`
+}
+
+type StructValuesHost struct {
+	Port int `config:"8080,The port to listen on"`
+}
+
+func DefaultStructValuesConfig() *StructValuesConfig {
+	return &StructValuesConfig{
+		Workers: runtime.NumCPU() + 1,
+		Ratio:   0.25,
+		Timeout: time.Minute,
+		Tags:    []string{"a", "b"},
+		Labels:  map[string]string{"team": "snakes"},
+		IP:      net.ParseIP("10.0.0.1"),
+		Server:  &StructValuesHost{Port: 9090},
+	}
+}
+
+func TestUseStructValues(t *testing.T) {
+	v := viper.New()
+	cmd := &cobra.Command{}
+	err := Bind(DefaultStructValuesConfig(), cmd, &Options{Viper: v, UseStructValues: true})
+	assertNil(t, err)
+
+	assertEqual(t, runtime.NumCPU()+1, v.GetInt("workers"))
+	assertEqual(t, "mamba", v.GetString("name"))
+
+	cfg := &StructValuesConfig{}
+	err = Load(cfg, &Options{Viper: v})
+	assertNil(t, err)
+
+	assertEqual(t, 0.25, cfg.Ratio)
+	assertEqual(t, time.Minute, cfg.Timeout)
+	assertSliceEqual(t, []string{"a", "b"}, cfg.Tags)
+	assertEqual(t, "snakes", cfg.Labels["team"])
+	assertEqual(t, "10.0.0.1", cfg.IP.String())
+	assertEqual(t, 9090, cfg.Server.Port)
+	assertEqual(t, "9090", cmd.Flags().Lookup("server.port").DefValue)
+}
+
+func TestStructValuesIgnoredByDefault(t *testing.T) {
+	v := viper.New()
+	err := Bind(DefaultStructValuesConfig(), &cobra.Command{}, &Options{Viper: v})
+	assertNil(t, err)
+
+	assertEqual(t, 1, v.GetInt("workers"))
+	assertEqual(t, 8080, v.GetInt("server.port"))
+}
+
+func TestStructValuesUsedForSamples(t *testing.T) {
+	opts := &Options{Viper: viper.New(), UseStructValues: true, ConfigCommand: true}
+	workers := fmt.Sprintf("workers: %d", runtime.NumCPU()+1)
+
+	sample, err := GenerateSample(DefaultStructValuesConfig(), FormatYAML, opts)
+	assertNil(t, err)
+	assertEqual(t, true, strings.Contains(string(sample), workers))
+	assertEqual(t, true, strings.Contains(string(sample), "port: 9090"))
+
+	schema, err := JSONSchema(DefaultStructValuesConfig(), opts)
+	assertNil(t, err)
+	assertEqual(t, true, strings.Contains(string(schema), fmt.Sprintf("\"default\": %d", runtime.NumCPU()+1)))
+
+	cfg := DefaultStructValuesConfig()
+	root := &cobra.Command{Use: "app"}
+	err = Bind(cfg, root, opts)
+	assertNil(t, err)
+
+	// Changes made to the struct after binding don't affect the defaults.
+	cfg.Workers = 0
+
+	out := &bytes.Buffer{}
+	root.SetOut(out)
+	root.SetArgs([]string{"config", "init"})
+	err = root.Execute()
+	assertNil(t, err)
+	assertEqual(t, true, strings.Contains(out.String(), workers))
+}
@@ -2,19 +2,38 @@ package internal
 
 import (
 	"encoding/csv"
+	"fmt"
 	"io"
+	"slices"
 	"strconv"
 	"strings"
 )
 
+// namedKeys are the keys accepted by the named tag form. Rule names are also accepted.
+var namedKeys = []string{"default", "desc", "description", "short", "shorthand", "persistent", "env", "layout", "configfile", "secret", "indexed", "encoding"}
+
 type Tag struct {
 	Description string
 	Default     string
 	Persistent  bool
 	Shorthand   string
+	Env         string
+	Rules       []Rule
+	Layout      string
+	ConfigFile  bool
+	Secret      bool
+	Indexed     int
+	Encoding    string
 }
 
+// Parse parses a config tag. Two forms are supported, the positional form
+// `default, description, persistent, shorthand, env, rules` and the named form
+// `default=8080;desc=Port to listen on;short=p;persistent`.
 func Parse(str string) (*Tag, error) {
+	if isNamed(str) {
+		return parseNamed(str)
+	}
+
 	t := &Tag{}
 
 	r := csv.NewReader(strings.NewReader(str))
@@ -49,7 +68,161 @@ func Parse(str string) (*Tag, error) {
 			t.Shorthand = strings.Trim(record[3], " ")
 		}
 
+		if len(record) >= 5 {
+			t.Env = strings.Trim(record[4], " ")
+		}
+
+		if len(record) >= 6 {
+			for _, r := range strings.Fields(record[5]) {
+				rule, err := ParseRule(r)
+				if err != nil {
+					return nil, err
+				}
+
+				t.Rules = append(t.Rules, rule)
+			}
+		}
+	}
+
+	return t, nil
+}
+
+// isNamed reports whether a tag uses the named form. This is the case when the first
+// part is a known key followed by `=`, or is a bare known key followed by `;`. A tag
+// with a single part that contains a comma is positional, so that defaults such as
+// `env=prod,The deploy env` keep working.
+func isNamed(str string) bool {
+	parts := splitNamed(str)
+	key, _, hasValue := strings.Cut(strings.TrimSpace(parts[0]), "=")
+	if !isNamedKey(key) {
+		return false
+	}
+
+	if len(parts) == 1 {
+		return hasValue && !strings.Contains(str, ",")
+	}
+
+	return true
+}
+
+func isNamedKey(key string) bool {
+	return slices.Contains(namedKeys, key) || slices.Contains(ruleNames, key)
+}
+
+func parseNamed(str string) (*Tag, error) {
+	t := &Tag{}
+
+	for _, part := range splitNamed(str) {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		key, value, hasValue := strings.Cut(part, "=")
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+
+		switch key {
+		case "default":
+			t.Default = value
+		case "desc", "description":
+			t.Description = value
+		case "short", "shorthand":
+			t.Shorthand = value
+		case "env":
+			t.Env = value
+		case "layout":
+			t.Layout = value
+		case "persistent":
+			t.Persistent = true
+			if hasValue {
+				b, err := strconv.ParseBool(value)
+				if err != nil {
+					return nil, err
+				}
+
+				t.Persistent = b
+			}
+		case "configfile":
+			t.ConfigFile = true
+			if hasValue {
+				b, err := strconv.ParseBool(value)
+				if err != nil {
+					return nil, err
+				}
+
+				t.ConfigFile = b
+			}
+		case "encoding":
+			if value != encodingBase64 && value != encodingHex {
+				return nil, fmt.Errorf("unknown encoding \"%s\", expected %s or %s", value, encodingBase64, encodingHex)
+			}
+
+			t.Encoding = value
+		case "indexed":
+			i, err := strconv.Atoi(value)
+			if err != nil {
+				return nil, err
+			}
+
+			t.Indexed = i
+		case "secret":
+			t.Secret = true
+			if hasValue {
+				b, err := strconv.ParseBool(value)
+				if err != nil {
+					return nil, err
+				}
+
+				t.Secret = b
+			}
+		default:
+			rule, err := ParseRule(part)
+			if err != nil {
+				return nil, err
+			}
+
+			t.Rules = append(t.Rules, rule)
+		}
+	}
+
+	for i := range t.Rules {
+		t.Rules[i].secret = t.Secret
 	}
 
 	return t, nil
 }
+
+// splitNamed splits a named tag on `;`, allowing a literal semicolon to be escaped as `\;`.
+func splitNamed(str string) []string {
+	parts := []string{}
+	current := strings.Builder{}
+	for i := 0; i < len(str); i++ {
+		if str[i] == '\\' && i+1 < len(str) && str[i+1] == ';' {
+			current.WriteByte(';')
+			i++
+			continue
+		}
+
+		if str[i] == ';' {
+			parts = append(parts, current.String())
+			current.Reset()
+			continue
+		}
+
+		current.WriteByte(str[i])
+	}
+
+	return append(parts, current.String())
+}
+
+// Choices returns the allowed values from a `oneof` rule, or nil if there is none.
+func (t *Tag) Choices() []string {
+	for _, r := range t.Rules {
+		if r.Name == "oneof" {
+			return r.values
+		}
+	}
+
+	return nil
+}
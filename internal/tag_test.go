@@ -0,0 +1,114 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+// Positional.
+func TestParsePositional(t *testing.T) {
+	tag, err := Parse("8080,The port to listen on,true,p,PORT,min=1")
+	assertNil(t, err)
+
+	assertEqual(t, "8080", tag.Default)
+	assertEqual(t, "The port to listen on", tag.Description)
+	assertEqual(t, true, tag.Persistent)
+	assertEqual(t, "p", tag.Shorthand)
+	assertEqual(t, "PORT", tag.Env)
+	assertEqual(t, "min", tag.Rules[0].Name)
+}
+
+func TestParsePositionalWithEquals(t *testing.T) {
+	tag, err := Parse("a=b,A default containing equals")
+	assertNil(t, err)
+
+	assertEqual(t, "a=b", tag.Default)
+	assertEqual(t, "A default containing equals", tag.Description)
+}
+
+func TestParsePositionalWithNamedKey(t *testing.T) {
+	tag, err := Parse("env=prod,The deploy env")
+	assertNil(t, err)
+
+	assertEqual(t, "env=prod", tag.Default)
+	assertEqual(t, "The deploy env", tag.Description)
+	assertEqual(t, "", tag.Env)
+
+	tag, err = Parse("env=PROD_ENV")
+	assertNil(t, err)
+
+	assertEqual(t, "", tag.Default)
+	assertEqual(t, "PROD_ENV", tag.Env)
+}
+
+func TestParsePositionalKeyword(t *testing.T) {
+	tag, err := Parse("persistent")
+	assertNil(t, err)
+
+	assertEqual(t, "persistent", tag.Default)
+	assertEqual(t, false, tag.Persistent)
+}
+
+// Named.
+func TestParseNamed(t *testing.T) {
+	tag, err := Parse("default=8080;desc=Port to listen on, or zero;short=p;persistent;env=PORT;required;max=65535")
+	assertNil(t, err)
+
+	assertEqual(t, "8080", tag.Default)
+	assertEqual(t, "Port to listen on, or zero", tag.Description)
+	assertEqual(t, true, tag.Persistent)
+	assertEqual(t, "p", tag.Shorthand)
+	assertEqual(t, "PORT", tag.Env)
+	assertEqual(t, 2, len(tag.Rules))
+	assertEqual(t, "max", tag.Rules[1].Name)
+}
+
+func TestParseNamedShorthandOnly(t *testing.T) {
+	tag, err := Parse("short=v")
+	assertNil(t, err)
+
+	assertEqual(t, "v", tag.Shorthand)
+	assertEqual(t, false, tag.Persistent)
+}
+
+func TestParseNamedEscapedSemicolon(t *testing.T) {
+	tag, err := Parse(`default=a\;b;desc=Semi`)
+	assertNil(t, err)
+
+	assertEqual(t, "a;b", tag.Default)
+	assertEqual(t, "Semi", tag.Description)
+}
+
+func TestParseNamedPersistentValue(t *testing.T) {
+	tag, err := Parse("persistent=false;desc=Not persistent")
+	assertNil(t, err)
+	assertEqual(t, false, tag.Persistent)
+
+	_, err = Parse("persistent=maybe;desc=Invalid")
+	assertError(t, err)
+}
+
+func TestParseNamedUnknownKey(t *testing.T) {
+	_, err := Parse("default=1;colour=red")
+	assertError(t, err)
+}
+
+type BindNamedTag struct {
+	Snakes []string `config:"default=[\"adder\",\"mamba\"];desc=A list of snakes, hsssss;short=s"`
+	Port   int      `config:"default=8080;desc=The port to listen on;short=p"`
+}
+
+func TestBindNamedTag(t *testing.T) {
+	cmd := &cobra.Command{}
+	err := Bind(BindNamedTag{}, cmd)
+	assertNil(t, err)
+
+	s, err := cmd.Flags().GetStringSlice("snakes")
+	assertNil(t, err)
+	assertSliceEqual(t, []string{"adder", "mamba"}, s)
+
+	f := cmd.Flags().ShorthandLookup("p")
+	assertEqual(t, "port", f.Name)
+	assertEqual(t, "The port to listen on", f.Usage)
+}
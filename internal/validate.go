@@ -0,0 +1,233 @@
+package internal
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ruleNames are the validation rules understood by ParseRule.
+var ruleNames = []string{"required", "min", "max", "len", "oneof", "enum", "regex"}
+
+// Rule is a single validation constraint from the config tag, e.g. `min=1`.
+type Rule struct {
+	Name string
+	Arg  string
+
+	num    float64
+	values []string
+	re     *regexp.Regexp
+	secret bool
+}
+
+// Violation describes a single failed rule for a key.
+type Violation struct {
+	Key     string
+	Rule    string
+	Message string
+}
+
+func (v Violation) String() string {
+	return fmt.Sprintf("\"%s\" %s", v.Key, v.Message)
+}
+
+// ParseRule parses a rule in the form `name` or `name=arg`.
+func ParseRule(str string) (Rule, error) {
+	name, arg, _ := strings.Cut(str, "=")
+	r := Rule{Name: name, Arg: arg}
+
+	switch name {
+	case "required":
+		if arg != "" {
+			return r, fmt.Errorf("rule \"%s\" does not take an argument", name)
+		}
+	case "min", "max", "len":
+		f, err := strconv.ParseFloat(arg, 64)
+		if err != nil {
+			// Durations are compared by their value in nanoseconds, so allow the
+			// bounds to be written as a duration, e.g. `max=1m`.
+			d, derr := time.ParseDuration(arg)
+			if derr != nil || name == "len" {
+				return r, fmt.Errorf("invalid argument for rule \"%s\": %w", name, err)
+			}
+
+			f = float64(d)
+		}
+
+		r.num = f
+	case "oneof", "enum":
+		r.Name = "oneof"
+		if arg == "" {
+			return r, fmt.Errorf("rule \"%s\" requires at least one value", name)
+		}
+
+		r.values = strings.Split(arg, "|")
+	case "regex":
+		re, err := regexp.Compile(arg)
+		if err != nil {
+			return r, fmt.Errorf("invalid argument for rule \"%s\": %w", name, err)
+		}
+
+		r.re = re
+	default:
+		return r, fmt.Errorf("unknown rule \"%s\"", name)
+	}
+
+	return r, nil
+}
+
+// check returns a message describing why v does not satisfy the rule, or an empty
+// string if it does.
+func (r Rule) check(v reflect.Value) string {
+	switch r.Name {
+	case "required":
+		if v.IsZero() || (hasLength(v) && v.Len() == 0) {
+			return "is required"
+		}
+	case "min":
+		if hasLength(v) && float64(v.Len()) < r.num {
+			return fmt.Sprintf("must have a length of at least %s", r.Arg)
+		}
+
+		if n, ok := number(v); ok && n < r.num {
+			return fmt.Sprintf("must be at least %s", r.Arg)
+		}
+	case "max":
+		if hasLength(v) && float64(v.Len()) > r.num {
+			return fmt.Sprintf("must have a length of at most %s", r.Arg)
+		}
+
+		if n, ok := number(v); ok && n > r.num {
+			return fmt.Sprintf("must be at most %s", r.Arg)
+		}
+	case "len":
+		if !hasLength(v) {
+			return fmt.Sprintf("does not support rule \"%s\" for type %s", r.Name, v.Type())
+		}
+
+		if float64(v.Len()) != r.num {
+			return fmt.Sprintf("must have a length of %s", r.Arg)
+		}
+	case "oneof":
+		for _, s := range elements(v) {
+			if !slices.Contains(r.values, s) {
+				return fmt.Sprintf("must be one of %s, got %s", strings.Join(r.values, ", "), r.quote(s))
+			}
+		}
+	case "regex":
+		for _, s := range elements(v) {
+			if !r.re.MatchString(s) {
+				return fmt.Sprintf("must match %s, got %s", r.Arg, r.quote(s))
+			}
+		}
+	}
+
+	return ""
+}
+
+// quote formats a value for a violation message, hiding it if the field is a secret.
+func (r Rule) quote(s string) string {
+	if r.secret {
+		return redacted
+	}
+
+	return fmt.Sprintf("\"%s\"", s)
+}
+
+// Validate checks every rule in the config tags of obj, returning a single error that
+// lists all of the violations.
+func Validate(obj any, options ...*Options) error {
+	return newBinder(options...).Validate(obj)
+}
+
+// Validate walks the same fields as Bind, checking the current value of each field
+// against the rules in its tag.
+func (b *Binder) Validate(obj any) error {
+	v := reflect.ValueOf(obj)
+	if v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+
+	if v.Kind() != reflect.Struct {
+		return NewInvalidTypeError(v.Kind(), "obj", fmt.Errorf("expected a struct or pointer to a struct"))
+	}
+
+	violations := []Violation{}
+	var check fieldFunc
+	check = func(n string, t *Tag, field reflect.StructField, v reflect.Value) error {
+		for _, r := range t.Rules {
+			if msg := r.check(v); msg != "" {
+				violations = append(violations, Violation{Key: n, Rule: r.Name, Message: msg})
+			}
+		}
+
+		// The fields of each element of a slice of structs are checked too, with keys
+		// such as `upstreams.0.host`.
+		if b.isStructSlice(field.Type) {
+			for i := 0; i < v.Len(); i++ {
+				e := v.Index(i)
+				if e.Kind() == reflect.Ptr && e.IsNil() {
+					continue
+				}
+
+				err := b.walkFields(b.elementPrefix(n, i), reflect.Indirect(e), check)
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	}
+
+	err := b.walkFields("", v, check)
+	if err != nil {
+		return err
+	}
+
+	if len(violations) > 0 {
+		return NewValidationError(violations)
+	}
+
+	return nil
+}
+
+func hasLength(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.String, reflect.Slice, reflect.Array, reflect.Map:
+		return true
+	}
+
+	return false
+}
+
+func number(v reflect.Value) (float64, bool) {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	}
+
+	return 0, false
+}
+
+// elements returns the string form of a value, or of each item for slices and arrays.
+func elements(v reflect.Value) []string {
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return []string{fmt.Sprint(v.Interface())}
+	}
+
+	s := make([]string, v.Len())
+	for i := range s {
+		s[i] = fmt.Sprint(v.Index(i).Interface())
+	}
+
+	return s
+}
@@ -0,0 +1,94 @@
+package internal
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// Validate.
+type ValidatePasses struct {
+	Port  int      `config:"8080,The port,false,,,required min=1 max=65535"`
+	Level string   `config:"info,The level,false,,,oneof=debug|info|warn"`
+	Name  string   `config:"mamba,The name,false,,,len=5 regex=^[a-z]+$"`
+	Tags  []string `config:",The tags,false,,,max=2"`
+}
+
+func TestValidatePasses(t *testing.T) {
+	err := Validate(ValidatePasses{Port: 8080, Level: "info", Name: "mamba", Tags: []string{"a"}})
+	assertNil(t, err)
+}
+
+func TestValidateAggregatesViolations(t *testing.T) {
+	err := Validate(&ValidatePasses{Port: 0, Level: "trace", Name: "Viper!", Tags: []string{"a", "b", "c"}})
+	assertErrorIs(t, err, &validationError{})
+
+	var verr *validationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("expected a validation error but got %v", err)
+	}
+
+	keys := []string{}
+	for _, v := range verr.Violations {
+		keys = append(keys, v.Key+":"+v.Rule)
+	}
+
+	assertSliceEqual(t, []string{"port:required", "port:min", "level:oneof", "name:len", "name:regex", "tags:max"}, keys)
+}
+
+type ValidateNested struct {
+	Server ValidateNestedServer `config:""`
+}
+
+type ValidateNestedServer struct {
+	Host string `config:",The host,false,,,required"`
+}
+
+func TestValidateNestedUsesKeys(t *testing.T) {
+	err := Validate(ValidateNested{}, &Options{Separator: "-"})
+
+	var verr *validationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("expected a validation error but got %v", err)
+	}
+
+	assertEqual(t, "server-host", verr.Violations[0].Key)
+}
+
+func TestLoadValidatesResolvedValues(t *testing.T) {
+	v := viper.New()
+	cmd := &cobra.Command{}
+	err := Bind(ValidatePasses{}, cmd, &Options{Viper: v})
+	assertNil(t, err)
+
+	err = cmd.ParseFlags([]string{"--port", "70000"})
+	assertNil(t, err)
+
+	err = Load(&ValidatePasses{}, &Options{Viper: v})
+	assertErrorIs(t, err, &validationError{})
+}
+
+type ValidateInvalidRule struct {
+	Port int `config:"8080,The port,false,,,between=1"`
+}
+
+func TestBindErrorsOnInvalidRule(t *testing.T) {
+	cmd := &cobra.Command{}
+	err := Bind(ValidateInvalidRule{}, cmd)
+	assertErrorIs(t, err, &tagParseError{})
+}
+
+type ValidateDuration struct {
+	Timeout time.Duration `config:"default=30s;desc=The timeout;min=1s;max=1m"`
+}
+
+func TestValidateDurationBounds(t *testing.T) {
+	err := Validate(ValidateDuration{Timeout: 30 * time.Second})
+	assertNil(t, err)
+
+	err = Validate(ValidateDuration{Timeout: 2 * time.Minute})
+	assertErrorIs(t, err, &validationError{})
+}
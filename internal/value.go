@@ -0,0 +1,91 @@
+package internal
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+var valueType = reflect.TypeOf((*pflag.Value)(nil)).Elem()
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+var binaryUnmarshalerType = reflect.TypeOf((*encoding.BinaryUnmarshaler)(nil)).Elem()
+
+// isCustom reports whether a type knows how to parse itself from a string, either by
+// implementing pflag.Value, encoding.TextUnmarshaler or encoding.BinaryUnmarshaler.
+// Both `T` and `*T` fields are supported as long as `*T` implements the interface.
+func isCustom(t reflect.Type) bool {
+	p := t
+	if t.Kind() != reflect.Ptr {
+		p = reflect.PointerTo(t)
+	}
+
+	return p.Implements(valueType) || p.Implements(textUnmarshalerType) || p.Implements(binaryUnmarshalerType)
+}
+
+// newCustom allocates a new value for a custom type, returning it as a pflag.Value and
+// the reflect.Value that should be assigned to the field once it has been set.
+func newCustom(t reflect.Type) (pflag.Value, reflect.Value) {
+	var p, target reflect.Value
+	if t.Kind() == reflect.Ptr {
+		p = reflect.New(t.Elem())
+		target = p
+	} else {
+		p = reflect.New(t)
+		target = p.Elem()
+	}
+
+	if v, ok := p.Interface().(pflag.Value); ok {
+		return v, target
+	}
+
+	return &textValue{p, t}, target
+}
+
+// textValue adapts types implementing encoding.TextUnmarshaler or encoding.BinaryUnmarshaler
+// to a pflag.Value.
+type textValue struct {
+	ptr reflect.Value
+	typ reflect.Type
+}
+
+func (v *textValue) Set(s string) error {
+	switch u := v.ptr.Interface().(type) {
+	case encoding.TextUnmarshaler:
+		return u.UnmarshalText([]byte(s))
+	case encoding.BinaryUnmarshaler:
+		return u.UnmarshalBinary([]byte(s))
+	}
+
+	return fmt.Errorf("%s cannot be set from a string", v.typ)
+}
+
+func (v *textValue) String() string {
+	switch m := v.ptr.Interface().(type) {
+	case encoding.TextMarshaler:
+		b, err := m.MarshalText()
+		if err == nil {
+			return string(b)
+		}
+	case encoding.BinaryMarshaler:
+		b, err := m.MarshalBinary()
+		if err == nil {
+			return string(b)
+		}
+	case fmt.Stringer:
+		return m.String()
+	}
+
+	return ""
+}
+
+func (v *textValue) Type() string {
+	t := v.typ
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	return strings.ToLower(t.Name())
+}
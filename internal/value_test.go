@@ -0,0 +1,115 @@
+package internal
+
+import (
+	"errors"
+	"log/slog"
+	"net"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// pflag.Value.
+type colour string
+
+func (c *colour) String() string { return string(*c) }
+func (c *colour) Type() string   { return "colour" }
+func (c *colour) Set(s string) error {
+	if s != "red" && s != "green" {
+		return errors.New("unknown colour")
+	}
+
+	*c = colour(s)
+	return nil
+}
+
+type BindValueSetsDefault struct {
+	Colour colour `config:"red,The colour to use"`
+}
+
+func TestBindValueSetsDefault(t *testing.T) {
+	cmd := &cobra.Command{}
+	err := Bind(BindValueSetsDefault{}, cmd)
+	assertNil(t, err)
+
+	f := cmd.Flags().Lookup("colour")
+	assertEqual(t, "red", f.DefValue)
+	assertEqual(t, "colour", f.Value.Type())
+
+	err = cmd.ParseFlags([]string{"--colour", "blue"})
+	assertError(t, err)
+}
+
+type BindValueInvalidDefaultReturnsError struct {
+	Colour colour `config:"blue,The colour to use"`
+}
+
+func TestBindValueInvalidDefaultReturnsError(t *testing.T) {
+	cmd := &cobra.Command{}
+	err := Bind(BindValueInvalidDefaultReturnsError{}, cmd)
+
+	assertErrorIs(t, err, &parseError{})
+}
+
+// encoding.TextUnmarshaler.
+type BindTextSetsDefault struct {
+	Address net.IP     `config:"127.0.0.1,The address to listen on"`
+	Level   slog.Level `config:"warn,The log level"`
+}
+
+func TestBindTextSetsDefault(t *testing.T) {
+	cmd := &cobra.Command{}
+	err := Bind(BindTextSetsDefault{}, cmd)
+	assertNil(t, err)
+
+	assertEqual(t, "127.0.0.1", cmd.Flags().Lookup("address").DefValue)
+	assertEqual(t, "WARN", cmd.Flags().Lookup("level").DefValue)
+	assertEqual(t, "level", cmd.Flags().Lookup("level").Value.Type())
+}
+
+type BindTextInvalidDefaultReturnsError struct {
+	Level slog.Level `config:"loud,The log level"`
+}
+
+func TestBindTextInvalidDefaultReturnsError(t *testing.T) {
+	cmd := &cobra.Command{}
+	err := Bind(BindTextInvalidDefaultReturnsError{}, cmd)
+
+	assertErrorIs(t, err, &parseError{})
+}
+
+// Populate.
+type PopulateCustom struct {
+	Colour   colour     `config:"red,The colour to use"`
+	Address  net.IP     `config:"127.0.0.1,The address to listen on"`
+	Level    slog.Level `config:"warn,The log level"`
+	Endpoint url.URL    `config:"https://example.com/api,The endpoint to call"`
+	Proxy    *url.URL   `config:",The proxy to use"`
+}
+
+func TestPopulateCustom(t *testing.T) {
+	opts := &Options{Viper: viper.New()}
+	cmd := &cobra.Command{}
+	err := Bind(PopulateCustom{}, cmd, opts)
+	assertNil(t, err)
+
+	err = cmd.ParseFlags([]string{"--colour", "green", "--level", "debug", "--proxy", "http://proxy:3128"})
+	assertNil(t, err)
+
+	opts.Viper.SetConfigType("yaml")
+	err = opts.Viper.ReadConfig(strings.NewReader("address: 10.0.0.1\n"))
+	assertNil(t, err)
+
+	cfg := &PopulateCustom{}
+	err = Load(cfg, opts)
+	assertNil(t, err)
+
+	assertEqual(t, colour("green"), cfg.Colour)
+	assertEqual(t, "10.0.0.1", cfg.Address.String())
+	assertEqual(t, slog.LevelDebug, cfg.Level)
+	assertEqual(t, "example.com", cfg.Endpoint.Host)
+	assertEqual(t, "proxy:3128", cfg.Proxy.Host)
+}
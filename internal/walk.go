@@ -0,0 +1,65 @@
+package internal
+
+import (
+	"reflect"
+	"strings"
+)
+
+// fieldFunc is called by walkFields for every tagged field that holds a value.
+type fieldFunc func(n string, t *Tag, field reflect.StructField, v reflect.Value) error
+
+// walkFields visits the same fields as processFields, but over a struct value rather
+// than a type, so that the fields can be read or set. Nil struct pointers are allocated
+// when the value is settable.
+func (b *Binder) walkFields(prefix string, v reflect.Value, fn fieldFunc) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		err := b.walkField(prefix, t.Field(i), v.Field(i), fn)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (b *Binder) walkField(prefix string, field reflect.StructField, v reflect.Value, fn fieldFunc) error {
+	n := b.key(prefix, field)
+
+	if string(field.Name[0]) != strings.ToUpper(string(field.Name[0])) {
+		return nil
+	}
+
+	k := field.Type.Kind()
+	tag, present := field.Tag.Lookup("config")
+
+	if !present {
+		return nil
+	}
+
+	t, err := Parse(tag)
+	if err != nil {
+		return NewTagParseError(tag, k, n, err)
+	}
+
+	switch {
+	case k == reflect.Struct && !b.isLeaf(field.Type):
+		return b.walkFields(n, v, fn)
+	case k == reflect.Ptr && !b.isLeaf(field.Type):
+		if field.Type.Elem().Kind() != reflect.Struct {
+			return NewInvalidTypeError(k, n)
+		}
+
+		if v.IsNil() {
+			if !v.CanSet() {
+				return b.walkFields(b.embeddedKey(prefix, n, field), reflect.New(field.Type.Elem()).Elem(), fn)
+			}
+
+			v.Set(reflect.New(field.Type.Elem()))
+		}
+
+		return b.walkFields(b.embeddedKey(prefix, n, field), v.Elem(), fn)
+	}
+
+	return fn(n, t, field, v)
+}
@@ -0,0 +1,212 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// WatchFunc is called with the previous and new values of a watched struct, and the keys
+// that changed between them.
+type WatchFunc func(old, new any, changed []string)
+
+// Watch loads the struct pointed to by obj, then watches the config file viper has read
+// for changes, or every one of Options.ConfigLayers if set. On each change the struct is
+// loaded again into a new value and validated, and if any key changed fn is called with
+// the previous and new values. A value that fails to load or validate is dropped, and the
+// error is passed to Options.OnReloadError if set. The returned func stops watching.
+//
+// obj is not written to once Watch returns. fn runs on the goroutine watching the file,
+// and is responsible for swapping the new value in where the rest of the program can see
+// it, e.g. under a mutex or with an atomic.Pointer.
+func Watch(obj any, fn WatchFunc, options ...*Options) (func(), error) {
+	v := reflect.ValueOf(obj)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return nil, NewInvalidTypeError(v.Kind(), "obj", fmt.Errorf("expected a non-nil pointer to a struct"))
+	}
+
+	b, err := watchBinder(v.Elem(), options...)
+	if err != nil {
+		return nil, err
+	}
+
+	files := b.watchedFiles()
+	if len(files) == 0 {
+		return nil, NewConfigError("", b.configKey, fmt.Errorf("no config file has been read"))
+	}
+
+	err = b.load(v)
+	if err != nil {
+		return nil, err
+	}
+
+	// Keep a copy of the current value to compare changes against, leaving obj alone.
+	cur := reflect.New(v.Elem().Type())
+	cur.Elem().Set(v.Elem())
+
+	w, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+
+	watched := map[string]bool{}
+	for _, f := range files {
+		f = filepath.Clean(f)
+		watched[f] = true
+
+		// Watch the directory rather than the file, so that files replaced by a rename or
+		// created later are seen.
+		err = w.Add(filepath.Dir(f))
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			w.Close()
+			return nil, err
+		}
+	}
+
+	go func() {
+		for {
+			select {
+			case e, ok := <-w.Events:
+				if !ok {
+					return
+				}
+
+				if watched[filepath.Clean(e.Name)] && (e.Has(fsnotify.Write) || e.Has(fsnotify.Create)) {
+					b.onReload(b.reloadFiles(cur, fn))
+				}
+			case err, ok := <-w.Errors:
+				if !ok {
+					return
+				}
+
+				b.onReload(err)
+			}
+		}
+	}()
+
+	once := sync.Once{}
+	return func() { once.Do(func() { w.Close() }) }, nil
+}
+
+// watchBinder returns the binder that bound the type of v with the same options, so that
+// the layers it merges are the ones reported for its command. If there is none, a new
+// binder is returned, with the key of the config file field found by walking v.
+func watchBinder(v reflect.Value, options ...*Options) (*Binder, error) {
+	if len(options) == 1 {
+		if b := registered(v.Type(), options[0]); b != nil {
+			return b, nil
+		}
+	}
+
+	b := newBinder(options...)
+	err := b.walkFields("", v, func(n string, t *Tag, _ reflect.StructField, _ reflect.Value) error {
+		if b.isConfigFile(n, t) {
+			b.configKey = n
+		}
+
+		return nil
+	})
+
+	return b, err
+}
+
+// watchedFiles returns the config files to watch: every config layer when there are any,
+// otherwise the file viper has read.
+func (b *Binder) watchedFiles() []string {
+	if len(b.opts.ConfigLayers) > 0 {
+		return b.layerFiles()
+	}
+
+	if f := b.viper().ConfigFileUsed(); f != "" {
+		return []string{f}
+	}
+
+	return nil
+}
+
+// reloadFiles reads the config files again before reloading the struct.
+func (b *Binder) reloadFiles(v reflect.Value, fn WatchFunc) error {
+	if len(b.opts.ConfigLayers) > 0 {
+		path := ""
+		if b.configKey != "" {
+			path = b.viper().GetString(b.configKey)
+		}
+
+		err := b.mergeLayers(path)
+		if err != nil {
+			return err
+		}
+	} else if err := b.viper().ReadInConfig(); err != nil {
+		return NewConfigError(b.viper().ConfigFileUsed(), b.configKey, err)
+	}
+
+	return b.reload(v, fn)
+}
+
+// onReload passes an error from reloading to Options.OnReloadError if set.
+func (b *Binder) onReload(err error) {
+	if err != nil && b.opts.OnReloadError != nil {
+		b.opts.OnReloadError(err)
+	}
+}
+
+// load populates and validates v.
+func (b *Binder) load(v reflect.Value) error {
+	err := b.Populate(v.Interface())
+	if err != nil {
+		return err
+	}
+
+	return b.Validate(v.Interface())
+}
+
+// reload loads a new value for the struct pointed to by v, and if any of its keys differ
+// from the current value swaps it in and passes both to fn.
+func (b *Binder) reload(v reflect.Value, fn WatchFunc) error {
+	next := reflect.New(v.Elem().Type())
+	err := b.load(next)
+	if err != nil {
+		return err
+	}
+
+	changed, err := b.diff(v.Elem(), next.Elem())
+	if err != nil || len(changed) == 0 {
+		return err
+	}
+
+	old := reflect.New(v.Elem().Type()).Elem()
+	old.Set(v.Elem())
+	v.Elem().Set(next.Elem())
+
+	fn(old.Interface(), next.Elem().Interface(), changed)
+	return nil
+}
+
+// diff returns the keys whose values differ between two values of the same struct, in
+// the order they are declared.
+func (b *Binder) diff(old, new reflect.Value) ([]string, error) {
+	values := map[string]any{}
+	err := b.walkFields("", old, func(n string, _ *Tag, _ reflect.StructField, v reflect.Value) error {
+		values[n] = v.Interface()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	changed := []string{}
+	err = b.walkFields("", new, func(n string, _ *Tag, _ reflect.StructField, v reflect.Value) error {
+		if !reflect.DeepEqual(values[n], v.Interface()) {
+			changed = append(changed, n)
+		}
+
+		return nil
+	})
+
+	return changed, err
+}
@@ -0,0 +1,182 @@
+package internal
+
+import (
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// Watch.
+type WatchConfig struct {
+	Level  string      `config:"info,The log level,false,,,oneof=debug|info|warn"`
+	Rate   int         `config:"10,The rate limit"`
+	Server WatchServer `config:""`
+}
+
+type WatchServer struct {
+	Port int `config:"8080,The port to listen on"`
+}
+
+type watchChange struct {
+	old, new WatchConfig
+	changed  []string
+}
+
+func TestWatchReloads(t *testing.T) {
+	path := writeConfig(t, t.TempDir(), "app.yaml", "level: info\nrate: 10\n")
+
+	v := viper.New()
+	v.SetConfigFile(path)
+	err := v.ReadInConfig()
+	assertNil(t, err)
+
+	changes := make(chan watchChange, 1)
+	errs := make(chan error, 1)
+	cfg := &WatchConfig{}
+	stop, err := Watch(cfg, func(old, new any, changed []string) {
+		changes <- watchChange{old.(WatchConfig), new.(WatchConfig), changed}
+	}, &Options{Viper: v, OnReloadError: func(err error) { errs <- err }})
+	assertNil(t, err)
+	defer stop()
+	assertEqual(t, 10, cfg.Rate)
+
+	// Replace the file in one go so the watcher never sees it partly written.
+	tmp := writeConfig(t, t.TempDir(), "app.yaml", "level: debug\nrate: 10\nserver:\n  port: 9090\n")
+	err = os.Rename(tmp, path)
+	assertNil(t, err)
+
+	select {
+	case c := <-changes:
+		assertSliceEqual(t, []string{"level", "server.port"}, c.changed)
+		assertEqual(t, "info", c.old.Level)
+		assertEqual(t, "debug", c.new.Level)
+		assertEqual(t, 9090, c.new.Server.Port)
+
+		// The new value is left for fn to swap in.
+		assertEqual(t, "info", cfg.Level)
+	case err := <-errs:
+		t.Fatalf("expected a change but got %v", err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the config to reload")
+	}
+}
+
+type WatchLayers struct {
+	A int `config:"1,The a value"`
+	B int `config:"1,The b value"`
+	C int `config:"1,The c value"`
+}
+
+func TestWatchReloadsLayers(t *testing.T) {
+	dir := t.TempDir()
+	base := writeConfig(t, dir, "base.yaml", "a: 5\n")
+	local := writeConfig(t, dir, "local.yaml", "b: 6\n")
+
+	opts := &Options{Viper: viper.New(), ConfigLayers: []string{base, local}}
+	cmd := &cobra.Command{Use: "app", Run: func(*cobra.Command, []string) {}}
+	err := Bind(WatchLayers{}, cmd, opts)
+	assertNil(t, err)
+
+	cmd.SetArgs([]string{})
+	err = cmd.Execute()
+	assertNil(t, err)
+
+	type change struct {
+		new     WatchLayers
+		changed []string
+	}
+
+	changes := make(chan change, 1)
+	errs := make(chan error, 1)
+	opts.OnReloadError = func(err error) { errs <- err }
+	cfg := &WatchLayers{}
+	stop, err := Watch(cfg, func(_, new any, changed []string) {
+		changes <- change{new.(WatchLayers), changed}
+	}, opts)
+	assertNil(t, err)
+	defer stop()
+	assertEqual(t, WatchLayers{A: 5, B: 6, C: 1}, *cfg)
+
+	tmp := writeConfig(t, t.TempDir(), "local.yaml", "b: 7\nc: 9\n")
+	err = os.Rename(tmp, local)
+	assertNil(t, err)
+
+	select {
+	case c := <-changes:
+		assertSliceEqual(t, []string{"b", "c"}, c.changed)
+		assertEqual(t, WatchLayers{A: 5, B: 7, C: 9}, c.new)
+
+		report := LayerReport(cmd)
+		assertEqual(t, base, report["a"])
+		assertEqual(t, local, report["c"])
+	case err := <-errs:
+		t.Fatalf("expected a change but got %v", err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the config to reload")
+	}
+}
+
+func TestWatchReloadKeepsInvalidValue(t *testing.T) {
+	v := viper.New()
+	b := newBinder(&Options{Viper: v})
+	v.SetDefault("level", "info")
+
+	cfg := &WatchConfig{}
+	called := false
+	fn := func(any, any, []string) { called = true }
+
+	err := b.reload(reflect.ValueOf(cfg), fn)
+	assertNil(t, err)
+	assertEqual(t, true, called)
+	assertEqual(t, "info", cfg.Level)
+
+	called = false
+	err = b.reload(reflect.ValueOf(cfg), fn)
+	assertNil(t, err)
+	assertEqual(t, false, called)
+
+	v.Set("level", "trace")
+	err = b.reload(reflect.ValueOf(cfg), fn)
+	assertErrorIs(t, err, &validationError{})
+	assertEqual(t, false, called)
+	assertEqual(t, "info", cfg.Level)
+}
+
+func TestWatchStops(t *testing.T) {
+	path := writeConfig(t, t.TempDir(), "app.yaml", "level: info\nrate: 10\n")
+
+	v := viper.New()
+	v.SetConfigFile(path)
+	err := v.ReadInConfig()
+	assertNil(t, err)
+
+	changes := make(chan []string, 1)
+	stop, err := Watch(&WatchConfig{}, func(_, _ any, changed []string) { changes <- changed }, &Options{Viper: v})
+	assertNil(t, err)
+	stop()
+	stop()
+
+	tmp := writeConfig(t, t.TempDir(), "app.yaml", "level: info\nrate: 20\n")
+	err = os.Rename(tmp, path)
+	assertNil(t, err)
+
+	select {
+	case c := <-changes:
+		t.Fatalf("expected no change once stopped but got %v", c)
+	case <-time.After(200 * time.Millisecond):
+	}
+}
+
+func TestWatchWithoutConfigFile(t *testing.T) {
+	_, err := Watch(&WatchConfig{}, func(any, any, []string) {}, &Options{Viper: viper.New()})
+	assertErrorIs(t, err, &configError{})
+}
+
+func TestWatchInvalidType(t *testing.T) {
+	_, err := Watch(WatchConfig{}, func(any, any, []string) {})
+	assertErrorIs(t, err, &invalidTypeError{})
+}
@@ -1,17 +1,42 @@
 package mamba
 
 import (
+	"io"
+	"reflect"
+
 	"github.com/scottkgregory/mamba/internal"
 	"github.com/spf13/cobra"
 )
 
 // Expose types from internal package in one place
 type Options = internal.Options
+type Violation = internal.Violation
+type Converter = internal.Converter
+type Explanation = internal.Explanation
+type Format = internal.Format
+
+const (
+	FormatYAML = internal.FormatYAML
+	FormatJSON = internal.FormatJSON
+	FormatTOML = internal.FormatTOML
+	FormatEnv  = internal.FormatEnv
+)
+
+const (
+	SourceFlag    = internal.SourceFlag
+	SourceEnv     = internal.SourceEnv
+	SourceConfig  = internal.SourceConfig
+	SourceDefault = internal.SourceDefault
+)
 
 var InvalidTypeError = internal.InvalidTypeError
 var BindError = internal.BindError
 var ParseError = internal.ParseError
 var TagParseError = internal.TagParseError
+var DecodeError = internal.DecodeError
+var ValidationError = internal.ValidationError
+var ConfigError = internal.ConfigError
+var AggregateError = internal.AggregateError
 
 // MustBind calls the mamba.Bind method and panics if an error is returned.
 func MustBind(obj any, cmd *cobra.Command, options ...*Options) {
@@ -20,6 +45,13 @@ func MustBind(obj any, cmd *cobra.Command, options ...*Options) {
 	}
 }
 
+// RegisterType registers a Converter that tells mamba how to bind fields of the given
+// type, e.g. `mamba.RegisterType(reflect.TypeOf(ByteSize(0)), byteSizeConverter{})`.
+// Registered converters are consulted before any of the built in types.
+func RegisterType(t reflect.Type, c Converter) {
+	internal.RegisterType(t, c)
+}
+
 // Bind recursively iterates over all properties of the given object, binding flags
 // for each one that is tagged with the `config` tag.
 //
@@ -28,3 +60,109 @@ func MustBind(obj any, cmd *cobra.Command, options ...*Options) {
 func Bind(obj any, cmd *cobra.Command, options ...*Options) error {
 	return internal.Bind(obj, cmd, options...)
 }
+
+// BindAll binds like Bind, but keeps going when a field fails to bind. Every error found
+// is returned, joined in an error which matches AggregateError. Each can still be checked
+// with errors.Is and errors.As, and ErrorFields lists the fields that failed.
+func BindAll(obj any, cmd *cobra.Command, options ...*Options) error {
+	return internal.BindAll(obj, cmd, options...)
+}
+
+// ErrorFields returns the paths of the fields that an error returned by Bind or BindAll
+// is for.
+func ErrorFields(err error) []string {
+	return internal.ErrorFields(err)
+}
+
+// MustLoad calls the mamba.Load method and panics if an error is returned.
+func MustLoad(obj any, options ...*Options) {
+	if err := Load(obj, options...); err != nil {
+		panic(err)
+	}
+}
+
+// Load populates the struct pointed to by obj with the values resolved by Viper. It
+// should be called after cobra has parsed the flags, e.g. from within `Run`, and be
+// given the same options that were passed to Bind so that the generated keys match.
+//
+// Values are resolved in the usual Viper order: flag, env, config file and then default.
+// Once loaded the struct is validated, see mamba.Validate.
+func Load(obj any, options ...*Options) error {
+	return internal.Load(obj, options...)
+}
+
+// Validate checks the values of obj against the rules in its config tags, e.g.
+// `required min=1 max=65535`. Every failing key is collected into a single error
+// which can be checked with errors.Is(err, mamba.ValidationError).
+func Validate(obj any, options ...*Options) error {
+	return internal.Validate(obj, options...)
+}
+
+// LayerReport returns, for each key bound to the command or its parents, the path of the
+// config layer that supplied its final value. Keys not set by any layer, or overridden by
+// a flag or env var, are omitted.
+func LayerReport(cmd *cobra.Command) map[string]string {
+	return internal.LayerReport(cmd)
+}
+
+// ReadConfig reads the config files, and secrets from files, for the fields bound to the
+// command or its parents. Bind installs a persistent pre-run hook that does this, but cobra
+// only runs the closest persistent pre-run hook, so a sub command with a hook of its own
+// should call ReadConfig from it.
+func ReadConfig(cmd *cobra.Command) error {
+	return internal.ReadConfig(cmd)
+}
+
+// Explain returns the final value of every key bound to the command or its parents, along
+// with where it came from: a flag, env var, config file or the default in the tag.
+func Explain(cmd *cobra.Command) []Explanation {
+	return internal.Explain(cmd)
+}
+
+// WriteExplain writes the output of mamba.Explain to w as a table.
+func WriteExplain(w io.Writer, cmd *cobra.Command) error {
+	return internal.WriteExplain(w, cmd)
+}
+
+// Dump writes the fully resolved configuration bound to the command, or its parents, to w
+// as YAML, JSON, TOML or in .env format. Keys are nested following the struct hierarchy.
+func Dump(w io.Writer, format Format, cmd *cobra.Command) error {
+	return internal.Dump(w, format, cmd)
+}
+
+// GenerateSample returns a sample config file for obj in the given format, containing
+// every key that would be bound with its default from the tag. YAML, TOML and env files
+// include the description of each key as a comment.
+func GenerateSample(obj any, format Format, options ...*Options) ([]byte, error) {
+	return internal.GenerateSample(obj, format, options...)
+}
+
+// JSONSchema returns a JSON Schema (draft 2020-12) for the config files accepted for obj,
+// with the type, default and description of every bound key.
+func JSONSchema(obj any, options ...*Options) ([]byte, error) {
+	return internal.JSONSchema(obj, options...)
+}
+
+// GenMarkdown writes a Markdown reference of the configuration bound to the command, or
+// its parents, listing the flag, env var, type, default and description of every key.
+func GenMarkdown(w io.Writer, cmd *cobra.Command) error {
+	return internal.GenMarkdown(w, cmd)
+}
+
+// GenMan writes a man page reference of the configuration bound to the command, or its
+// parents.
+func GenMan(w io.Writer, cmd *cobra.Command) error {
+	return internal.GenMan(w, cmd)
+}
+
+// Watch loads the struct pointed to by ptr, then reloads it whenever the config file read
+// by viper, or any of the config layers, changes. The new value is validated and, if any
+// key changed, passed to fn along with the previous value and the keys that changed.
+// ptr is not written to once Watch returns. fn runs on the goroutine watching the file,
+// so it should swap the new value in under a mutex or with an atomic.Pointer. Calling
+// stop stops watching the files.
+func Watch[T any](ptr *T, fn func(old, new T, changed []string), options ...*Options) (stop func(), err error) {
+	return internal.Watch(ptr, func(old, new any, changed []string) {
+		fn(old.(T), new.(T), changed)
+	}, options...)
+}